@@ -57,6 +57,8 @@ var buildCmd = &cobra.Command{
 		case "cs", "go", "java", "js", "ts":
 			err = modules.ExecuteBuild(buildData, data)
 			abort(err)
+		case "py", "rs":
+			abort(buildNative(data, output, os, arch))
 		default:
 			abort(fmt.Errorf("'build' is not supported for '%s'", data.Lang))
 		}
@@ -68,4 +70,6 @@ func init() {
 	buildCmd.Flags().StringP("output", "o", "", "The name of the output file.")
 	buildCmd.Flags().StringP("os", "", "current", "The target OS for compiled languages. (possible values: windows, macos, linux)")
 	buildCmd.Flags().StringP("arch", "", "current", "The target architecture for compiled languages. (possible values: x64, x86, arm32, arm64)")
+	buildCmd.RegisterFlagCompletionFunc("os", completeValues("current", "windows", "macos", "linux"))
+	buildCmd.RegisterFlagCompletionFunc("arch", completeValues("current", "x64", "x86", "arm32", "arm64"))
 }
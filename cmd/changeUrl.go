@@ -13,9 +13,10 @@ import (
 
 // changeUrlCmd represents the changeUrl command
 var changeUrlCmd = &cobra.Command{
-	Use:   "change-url",
-	Short: "Permanently switch to a different game URL.",
-	Args:  cobra.RangeArgs(0, 1),
+	Use:               "change-url",
+	Short:             "Permanently switch to a different game URL.",
+	Args:              cobra.RangeArgs(0, 1),
+	ValidArgsFunction: completeGameURL,
 	Run: func(cmd *cobra.Command, args []string) {
 		root, err := cgfile.FindProjectRoot()
 		abort(err)
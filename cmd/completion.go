@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Bananenpro/cli"
+	"github.com/adrg/xdg"
+	"github.com/code-game-project/go-utils/external"
+	"github.com/code-game-project/go-utils/server"
+	"github.com/code-game-project/go-utils/sessions"
+	"github.com/spf13/cobra"
+)
+
+// completionInstallCmd represents the completion install command
+var completionInstallCmd = &cobra.Command{
+	Use:       "install [bash|zsh|fish]",
+	Short:     "Install the autocompletion script for the current or the specified shell.",
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	Run: func(cmd *cobra.Command, args []string) {
+		var shell string
+		if len(args) > 0 {
+			shell = args[0]
+		} else {
+			shell = filepath.Base(os.Getenv("SHELL"))
+			if shell == "." || shell == "" {
+				abort(fmt.Errorf("failed to detect the current shell (specify one of bash, zsh or fish)"))
+			}
+			cli.Print("Detected shell: %s", shell)
+		}
+
+		var path string
+		var err error
+		switch shell {
+		case "bash":
+			path = filepath.Join(xdg.DataHome, "bash-completion", "completions", "codegame")
+			err = writeCompletion(path, func() error { return rootCmd.GenBashCompletionFileV2(path, true) })
+		case "zsh":
+			path = filepath.Join(xdg.DataHome, "zsh", "site-functions", "_codegame")
+			err = writeCompletion(path, func() error { return rootCmd.GenZshCompletionFile(path) })
+		case "fish":
+			path = filepath.Join(xdg.ConfigHome, "fish", "completions", "codegame.fish")
+			err = writeCompletion(path, func() error { return rootCmd.GenFishCompletionFile(path, true) })
+		default:
+			abort(fmt.Errorf("unsupported shell '%s' (possible values: bash, zsh, fish)", shell))
+		}
+		abortf("Failed to install completion script: %s", err)
+
+		cli.Success("Successfully installed the completion script to '%s'.", path)
+		switch shell {
+		case "bash":
+			cli.Print("The script is loaded automatically by bash-completion in new shells.")
+		case "zsh":
+			cli.Print("Make sure the following lines are in your ~/.zshrc before compinit is called:")
+			cli.PrintColor(cli.Cyan, "  fpath=(%s $fpath)", filepath.Dir(path))
+			cli.PrintColor(cli.Cyan, "  autoload -U compinit; compinit")
+		case "fish":
+			cli.Print("The script is loaded automatically in new shells.")
+		}
+	},
+}
+
+func writeCompletion(path string, generate func() error) error {
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+	return generate()
+}
+
+// initCompletionCmd adds the default completion command of cobra and extends it with the install command.
+func initCompletionCmd() {
+	rootCmd.InitDefaultCompletionCmd()
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == "completion" {
+			c.AddCommand(completionInstallCmd)
+			return
+		}
+	}
+}
+
+var languageNames = map[string]string{
+	"cs":   "C#",
+	"go":   "Go",
+	"java": "Java",
+	"js":   "JavaScript",
+	"py":   "Python",
+	"rs":   "Rust",
+	"ts":   "TypeScript",
+}
+
+// completeGameURLs returns the game URLs of all sessions and of the current project.
+func completeGameURLs() []string {
+	urls := make([]string, 0)
+	seen := make(map[string]bool)
+	if gameURL := findGameURL(); gameURL != "" {
+		urls = append(urls, gameURL+"\tcurrent project")
+		seen[gameURL] = true
+	}
+	sessionURLs, _ := sessions.ListGames()
+	sort.Strings(sessionURLs)
+	for _, u := range sessionURLs {
+		if !seen[u] {
+			urls = append(urls, u)
+			seen[u] = true
+		}
+	}
+	return urls
+}
+
+// completeGameURL completes the first argument with game URLs.
+func completeGameURL(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeGameURLs(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeSession completes the arguments `<url> <username>` of session commands.
+func completeSession(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return completeGameURLs(), cobra.ShellCompDirectiveNoFileComp
+	case 1:
+		usernames, _ := sessions.ListUsernames(args[0])
+		sort.Strings(usernames)
+		return usernames, cobra.ShellCompDirectiveNoFileComp
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeGameId completes the arguments `<url> <game_id>` with the public games of the server.
+func completeGameId(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return completeGameURLs(), cobra.ShellCompDirectiveNoFileComp
+	case 1:
+		return listGameIds(args[0]), cobra.ShellCompDirectiveNoFileComp
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+func listGameIds(gameURL string) []string {
+	api, err := server.NewAPI(external.TrimURL(gameURL))
+	if err != nil {
+		return nil
+	}
+	_, public, err := api.ListGames(false, false)
+	if err != nil {
+		return nil
+	}
+	ids := make([]string, 0, len(public))
+	for _, g := range public {
+		description := fmt.Sprintf("%d players", g.Players)
+		if g.Protected {
+			description += ", protected"
+		}
+		ids = append(ids, g.Id+"\t"+description)
+	}
+	return ids
+}
+
+// completeLanguage completes the --lang flag with the languages supported for the project type in the first argument.
+func completeLanguage(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var languages []string
+	if len(args) > 0 && supportedLanguages[strings.ToLower(args[0])] != nil {
+		languages = supportedLanguages[strings.ToLower(args[0])]
+	} else {
+		languages = append([]string{}, supportedLanguages["client"]...)
+		for _, l := range supportedLanguages["server"] {
+			if !containsString(languages, l) {
+				languages = append(languages, l)
+			}
+		}
+	}
+	completions := make([]string, 0, len(languages))
+	for _, l := range languages {
+		completions = append(completions, l+"\t"+languageNames[l])
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeValues returns a completion function for a fixed set of values.
+func completeValues(values ...string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return values, cobra.ShellCompDirectiveNoFileComp
+	}
+}
@@ -0,0 +1,375 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Bananenpro/cli"
+	"github.com/code-game-project/go-utils/cggenevents"
+	"github.com/code-game-project/go-utils/server"
+	"github.com/code-game-project/go-utils/sessions"
+	"github.com/gorilla/websocket"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// connectCmd represents the connect command
+var connectCmd = &cobra.Command{
+	Use:               "connect",
+	Short:             "Create or join a game and interact with it in a REPL.",
+	Args:              cobra.RangeArgs(0, 2),
+	ValidArgsFunction: completeGameId,
+	Run: func(cmd *cobra.Command, args []string) {
+		var gameURL string
+		var gameId string
+		var err error
+		if len(args) > 0 {
+			gameURL = args[0]
+			if len(args) > 1 {
+				gameId = args[1]
+			}
+		} else if gameURL = findGameURL(); gameURL != "" {
+			cli.Print("Game URL: %s", gameURL)
+		} else {
+			gameURL, err = cli.Input("Game URL:")
+			abort(err)
+		}
+
+		username, err := cmd.Flags().GetString("username")
+		abort(err)
+		if username != "" {
+			abort(validateUsername(username))
+		}
+		joinSecret, err := cmd.Flags().GetString("join-secret")
+		abort(err)
+
+		api, err := server.NewAPI(gameURL)
+		abort(err)
+
+		if gameId == "" {
+			public, err := cmd.Flags().GetBool("public")
+			abort(err)
+			protected, err := cmd.Flags().GetBool("protected")
+			abort(err)
+			gameId, joinSecret, err = createGame(api, public, protected, nil)
+			abortf("Failed to create game: %s", err)
+			cli.Print("Created game: %s", gameId)
+			if joinSecret != "" {
+				cli.Print("Join secret: %s", joinSecret)
+			}
+		} else if joinSecret == "" && !cmd.Flags().Changed("join-secret") {
+			joinSecret, err = cli.InputOptional("Join secret (optional):")
+			abort(err)
+		}
+
+		if username == "" {
+			username, err = cli.Input("Username:", cli.Regexp(usernameRegex, usernameRegexMessage))
+			abort(err)
+		}
+
+		playerId, playerSecret, err := joinGame(api, gameId, username, joinSecret)
+		abortf("Failed to join game: %s", err)
+
+		session := sessions.NewSession(gameURL, username, gameId, playerId, playerSecret)
+		err = saveSession(session)
+		abortf("Failed to save session: %s", err)
+		cli.Print("Joined game as %s@%s.", session.Username, session.GameURL)
+
+		repl, err := newREPL(api)
+		abort(err)
+
+		conn, _, err := websocket.DefaultDialer.Dial(websocketURL(api, fmt.Sprintf("/games/%s/connect?player_id=%s&player_secret=%s", gameId, url.QueryEscape(playerId), url.QueryEscape(playerSecret))), nil)
+		abortf("Failed to connect to game: %s", err)
+		defer conn.Close()
+
+		abort(repl.run(conn))
+	},
+}
+
+type repl struct {
+	events   []string
+	commands []string
+
+	lock   sync.Mutex
+	out    io.Writer
+	filter map[string]bool
+	hidden map[string]bool
+}
+
+var replHelp = `Usage:
+  <command> [json data]   Send a command to the game server.
+  /filter [event...]      Only show the specified events. Without arguments all events are shown.
+  /hide [event...]        Hide the specified events. Without arguments no events are hidden.
+  /events                 List all events.
+  /commands               List all commands.
+  /help                   Show this help.
+  /quit                   Close the connection.`
+
+func newREPL(api *server.API) (*repl, error) {
+	r := &repl{
+		out:    os.Stdout,
+		filter: make(map[string]bool),
+		hidden: make(map[string]bool),
+	}
+
+	cge, err := api.GetCGEFile()
+	if err != nil {
+		return nil, err
+	}
+	cgeVersion, err := cggenevents.ParseCGEVersion(cge)
+	if err != nil {
+		return nil, err
+	}
+	r.events, r.commands, err = cggenevents.GetEventNames(api.BaseURL(), cgeVersion)
+	if err != nil {
+		cli.Warn("Failed to load event names, completion is disabled: %s", err)
+	}
+	sort.Strings(r.events)
+	sort.Strings(r.commands)
+	return r, nil
+}
+
+// run reads commands from stdin and sends them to conn until the connection is closed or the user quits.
+// Tab completion is only available if stdin is a terminal.
+func (r *repl) run(conn *websocket.Conn) error {
+	var readLine func() (string, error)
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		state, err := term.MakeRaw(int(os.Stdin.Fd()))
+		if err != nil {
+			return err
+		}
+		defer term.Restore(int(os.Stdin.Fd()), state)
+
+		terminal := term.NewTerminal(struct {
+			io.Reader
+			io.Writer
+		}{os.Stdin, os.Stdout}, "> ")
+		terminal.AutoCompleteCallback = r.complete
+		r.out = terminal
+		readLine = terminal.ReadLine
+	} else {
+		scanner := bufio.NewScanner(os.Stdin)
+		readLine = func() (string, error) {
+			if !scanner.Scan() {
+				if scanner.Err() != nil {
+					return "", scanner.Err()
+				}
+				return "", io.EOF
+			}
+			return scanner.Text(), nil
+		}
+	}
+
+	r.print("%sConnected. Type /help for a list of commands.%s\n", cli.Green, cli.Reset)
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			var msg wsMessage
+			err := conn.ReadJSON(&msg)
+			if err != nil {
+				r.print("%sConnection closed.%s\n", cli.Red, cli.Reset)
+				return
+			}
+			r.printEvent(msg)
+		}
+	}()
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		for {
+			line, err := readLine()
+			if err != nil {
+				return
+			}
+			lines <- line
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return nil
+		case line, ok := <-lines:
+			if !ok {
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return nil
+			}
+			if r.handleLine(conn, strings.TrimSpace(line)) {
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return nil
+			}
+		}
+	}
+}
+
+// handleLine executes a single line of user input. It returns true if the REPL should quit.
+func (r *repl) handleLine(conn *websocket.Conn, line string) bool {
+	if line == "" {
+		return false
+	}
+
+	fields := strings.Fields(line)
+	switch fields[0] {
+	case "/quit", "/exit":
+		return true
+	case "/help":
+		r.print("%s\n", replHelp)
+	case "/events":
+		r.print("%s\n", strings.Join(r.events, "\n"))
+	case "/commands":
+		r.print("%s\n", strings.Join(r.commands, "\n"))
+	case "/filter":
+		r.lock.Lock()
+		r.filter = make(map[string]bool, len(fields)-1)
+		for _, f := range fields[1:] {
+			r.filter[f] = true
+		}
+		r.lock.Unlock()
+	case "/hide":
+		r.lock.Lock()
+		r.hidden = make(map[string]bool, len(fields)-1)
+		for _, f := range fields[1:] {
+			r.hidden[f] = true
+		}
+		r.lock.Unlock()
+	default:
+		if strings.HasPrefix(fields[0], "/") {
+			r.print("%sUnknown REPL command: %s%s\n", cli.Red, fields[0], cli.Reset)
+			return false
+		}
+		r.send(conn, fields[0], strings.TrimSpace(strings.TrimPrefix(line, fields[0])))
+	}
+	return false
+}
+
+func (r *repl) send(conn *websocket.Conn, name, data string) {
+	if len(r.commands) > 0 && !containsString(r.commands, name) {
+		r.print("%sUnknown command: %s%s\n", cli.Red, name, cli.Reset)
+		return
+	}
+
+	msg := wsMessage{
+		Name: name,
+	}
+	if data != "" {
+		if !json.Valid([]byte(data)) {
+			r.print("%sInvalid JSON data.%s\n", cli.Red, cli.Reset)
+			return
+		}
+		msg.Data = json.RawMessage(data)
+	}
+
+	err := conn.WriteJSON(msg)
+	if err != nil {
+		r.print("%sFailed to send command: %s%s\n", cli.Red, err, cli.Reset)
+	}
+}
+
+func (r *repl) printEvent(msg wsMessage) {
+	r.lock.Lock()
+	hidden := r.hidden[msg.Name] || (len(r.filter) > 0 && !r.filter[msg.Name])
+	r.lock.Unlock()
+	if hidden {
+		return
+	}
+
+	data := "{}"
+	if len(msg.Data) > 0 {
+		var buffer bytes.Buffer
+		if json.Indent(&buffer, msg.Data, "", "  ") == nil {
+			data = buffer.String()
+		} else {
+			data = string(msg.Data)
+		}
+	}
+	r.print("%s%s%s %s\n", cli.CyanBold, msg.Name, cli.Reset, data)
+}
+
+func (r *repl) print(format string, a ...any) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	fmt.Fprintf(r.out, format, a...)
+}
+
+// complete implements term.Terminal.AutoCompleteCallback.
+func (r *repl) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' || pos != len(line) {
+		return "", 0, false
+	}
+
+	fields := strings.Fields(line)
+	var candidates []string
+	var word string
+	if len(fields) == 0 || (len(fields) == 1 && !strings.HasSuffix(line, " ")) {
+		candidates = append([]string{"/commands", "/events", "/filter", "/help", "/hide", "/quit"}, r.commands...)
+		if len(fields) == 1 {
+			word = fields[0]
+		}
+	} else if fields[0] == "/filter" || fields[0] == "/hide" {
+		candidates = r.events
+		if !strings.HasSuffix(line, " ") {
+			word = fields[len(fields)-1]
+		}
+	} else {
+		return "", 0, false
+	}
+
+	matches := make([]string, 0)
+	for _, c := range candidates {
+		if strings.HasPrefix(c, word) {
+			matches = append(matches, c)
+		}
+	}
+	if len(matches) == 0 {
+		return "", 0, false
+	}
+
+	completion := commonPrefix(matches)
+	if len(matches) == 1 {
+		completion += " "
+	} else if completion == word {
+		r.print("%s\n", strings.Join(matches, "  "))
+		return "", 0, false
+	}
+
+	newLine := line[:len(line)-len(word)] + completion
+	return newLine, len(newLine), true
+}
+
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, v := range values[1:] {
+		for !strings.HasPrefix(v, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	rootCmd.AddCommand(connectCmd)
+	connectCmd.Flags().StringP("username", "u", "", "The username to join the game with.")
+	connectCmd.Flags().StringP("join-secret", "", "", "The join secret of a protected game.")
+	connectCmd.Flags().BoolP("public", "", false, "Make the created game public.")
+	connectCmd.Flags().BoolP("protected", "", false, "Make the created game protected.")
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import "golang.org/x/crypto/scrypt"
+
+// The scrypt parameters of deriveKey. They must never change, because the derived keys are persisted indirectly:
+// the session vault and encrypted session exports are encrypted with them and 'share serve' stores them as password hashes.
+const (
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+)
+
+// deriveKey derives a 256 bit key from passphrase and salt using scrypt.
+// The same passphrase and salt always result in the same key, also across versions of the CLI.
+func deriveKey(passphrase string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptKeyLen)
+}
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// TestDeriveKey pins the output of deriveKey. If it fails, existing vaults, encrypted exports and Share password hashes can no longer be opened.
+func TestDeriveKey(t *testing.T) {
+	key, err := deriveKey("passphrase", []byte("codegame-salt"))
+	if err != nil {
+		t.Fatalf("deriveKey: %s", err)
+	}
+	want := "6ecb2d827c0ada5b2c3ea2f15e61ae6199bf2a210bb371517b70fa79ddf656c5"
+	if got := hex.EncodeToString(key); got != want {
+		t.Errorf("deriveKey = %s, want %s", got, want)
+	}
+}
@@ -20,9 +20,10 @@ var cgDebugPath = filepath.Join(xdg.DataHome, "codegame", "bin", "cg-debug")
 
 // debugCmd represents the debug command
 var debugCmd = &cobra.Command{
-	Use:   "debug",
-	Short: "View debug logs of a game server.",
-	Args:  cobra.RangeArgs(0, 1),
+	Use:               "debug",
+	Short:             "View debug logs of a game server.",
+	Args:              cobra.RangeArgs(0, 1),
+	ValidArgsFunction: completeGameURL,
 	Run: func(cmd *cobra.Command, args []string) {
 		var url string
 		var err error
@@ -20,9 +20,10 @@ import (
 var docsStyle string
 
 var docsCmd = &cobra.Command{
-	Use:   "docs",
-	Short: "View the documention of CodeGame or a specific game in your webbrowser.",
-	Args:  cobra.RangeArgs(0, 1),
+	Use:               "docs",
+	Short:             "View the documention of CodeGame or a specific game in your webbrowser.",
+	Args:              cobra.RangeArgs(0, 1),
+	ValidArgsFunction: completeGameURL,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 0 {
 			cli.Print("Opening documentation...")
@@ -1,12 +1,29 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"net"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/Bananenpro/cli"
+	"github.com/code-game-project/go-utils/cgfile"
+	"github.com/code-game-project/go-utils/cggenevents"
+	"github.com/code-game-project/go-utils/config"
+	cgExec "github.com/code-game-project/go-utils/exec"
+	"github.com/code-game-project/go-utils/external"
+	"github.com/code-game-project/go-utils/semver"
+	"github.com/code-game-project/go-utils/server"
 	"github.com/spf13/cobra"
 )
 
@@ -14,6 +31,14 @@ type doctorRule interface {
 	Check() bool
 	ErrMessage() string
 	SuccessMessage() string
+	// Required reports whether a failure of the rule makes doctor fail.
+	Required() bool
+}
+
+// doctorRuleFixable is implemented by rules which can be fixed by installing a package.
+type doctorRuleFixable interface {
+	// FixCommand returns the command which installs the missing package with manager or nil if there is none.
+	FixCommand(manager packageManager) []string
 }
 
 type doctorRuleInactive struct{}
@@ -30,6 +55,10 @@ func (d doctorRuleInactive) SuccessMessage() string {
 	return ""
 }
 
+func (d doctorRuleInactive) Required() bool {
+	return false
+}
+
 type doctorRuleTool struct {
 	names   []string
 	message string
@@ -74,6 +103,208 @@ func (d doctorRuleTool) SuccessMessage() string {
 	panic("SuccessMessage() called but tool is not installed")
 }
 
+func (d doctorRuleTool) Required() bool {
+	return true
+}
+
+// doctorRuleVersion checks that a tool is installed in at least a minimum version.
+// The version is extracted from the output of the tool with the first submatch of pattern.
+// A missing tool is not required unless the rule belongs to the language of the current project, but an outdated one always is.
+type doctorRuleVersion struct {
+	// commands are alternative names (including leading arguments) of the tool. The first one which satisfies the rule is used.
+	commands   [][]string
+	args       []string
+	pattern    *regexp.Regexp
+	minVersion string
+	message    string
+	packages   map[string]string
+	required   bool
+
+	checked   bool
+	installed bool
+	name      string
+	version   string
+}
+
+func newDoctorRuleVersion(message, name string, args []string, pattern, minVersion string, packages map[string]string) doctorRule {
+	return newDoctorRuleVersionAlternatives(message, [][]string{{name}}, args, pattern, minVersion, packages)
+}
+
+// newDoctorRuleVersionAlternatives returns a version rule for a tool, which is available under several commands.
+// If there is more than one command, only commands whose output matches pattern count as installed.
+func newDoctorRuleVersionAlternatives(message string, commands [][]string, args []string, pattern, minVersion string, packages map[string]string) doctorRule {
+	return &doctorRuleVersion{
+		commands:   commands,
+		args:       args,
+		pattern:    regexp.MustCompile(pattern),
+		minVersion: minVersion,
+		message:    message,
+		packages:   packages,
+	}
+}
+
+func (d *doctorRuleVersion) Check() bool {
+	if !d.checked {
+		d.checked = true
+		for _, command := range d.commands {
+			if _, err := exec.LookPath(command[0]); err != nil {
+				continue
+			}
+			version := d.commandVersion(command)
+			if version == "" && len(d.commands) > 1 {
+				continue
+			}
+			// Report the first installed command unless a later one satisfies the rule.
+			if !d.installed || d.satisfied(version) {
+				d.installed = true
+				d.name = strings.Join(command, " ")
+				d.version = version
+			}
+			if d.satisfied(version) {
+				break
+			}
+		}
+	}
+	return d.installed && d.satisfied(d.version)
+}
+
+// commandVersion runs command with the version arguments of the rule and returns the extracted version or an empty string.
+func (d *doctorRuleVersion) commandVersion(command []string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// Some tools like `java -version` print their version to stderr or exit with an error if only parts of them are installed.
+	out, _ := exec.CommandContext(ctx, command[0], append(command[1:len(command):len(command)], d.args...)...).CombinedOutput()
+	if match := d.pattern.FindSubmatch(out); match != nil {
+		return string(match[1])
+	}
+	return ""
+}
+
+func (d *doctorRuleVersion) satisfied(version string) bool {
+	if d.minVersion == "" {
+		return true
+	}
+	return version != "" && versionAtLeast(version, d.minVersion)
+}
+
+func (d *doctorRuleVersion) ErrMessage() string {
+	if !d.installed {
+		return d.message
+	}
+	if d.version == "" {
+		return fmt.Sprintf("Failed to determine the version of `%s`. At least version %s is required. %s", d.name, d.minVersion, d.message)
+	}
+	return fmt.Sprintf("`%s` %s is installed, but at least version %s is required. %s", d.name, d.version, d.minVersion, d.message)
+}
+
+func (d *doctorRuleVersion) SuccessMessage() string {
+	if d.version == "" {
+		return fmt.Sprintf("`%s` is installed.", d.name)
+	}
+	return fmt.Sprintf("`%s` %s is installed.", d.name, d.version)
+}
+
+func (d *doctorRuleVersion) Required() bool {
+	return d.installed || d.required
+}
+
+func (d *doctorRuleVersion) FixCommand(manager packageManager) []string {
+	pkg, ok := d.packages[manager.name]
+	if !ok {
+		return nil
+	}
+	return append(manager.installCommand(), strings.Fields(pkg)...)
+}
+
+// doctorRuleCheck is a rule implemented by a function, which returns the success message or the reason of the failure.
+// Rules whose check succeeds with an empty message are skipped.
+type doctorRuleCheck struct {
+	check    func() (string, error)
+	required bool
+
+	message string
+	err     error
+}
+
+func newDoctorRuleCheck(required bool, check func() (string, error)) doctorRule {
+	return &doctorRuleCheck{
+		check:    check,
+		required: required,
+	}
+}
+
+func (d *doctorRuleCheck) Check() bool {
+	d.message, d.err = d.check()
+	return d.err == nil
+}
+
+func (d *doctorRuleCheck) ErrMessage() string {
+	return d.err.Error()
+}
+
+func (d *doctorRuleCheck) SuccessMessage() string {
+	return d.message
+}
+
+func (d *doctorRuleCheck) Required() bool {
+	return d.required
+}
+
+// versionAtLeast reports whether version is greater than or equal to minVersion.
+func versionAtLeast(version, minVersion string) bool {
+	major, minor, patch, err := semver.ParseVersion(version)
+	if err != nil {
+		return false
+	}
+	minMajor, minMinor, minPatch, err := semver.ParseVersion(minVersion)
+	if err != nil {
+		return false
+	}
+	if major != minMajor {
+		return major > minMajor
+	}
+	if minor != minMinor {
+		return minor > minMinor
+	}
+	return patch >= minPatch
+}
+
+// packageManager is a system package manager, which can be used by 'codegame doctor --fix' to install missing tools.
+type packageManager struct {
+	name    string
+	command []string
+	sudo    bool
+}
+
+var packageManagers = []packageManager{
+	{name: "brew", command: []string{"brew", "install"}},
+	{name: "apt-get", command: []string{"apt-get", "install", "-y"}, sudo: true},
+	{name: "dnf", command: []string{"dnf", "install", "-y"}, sudo: true},
+	{name: "pacman", command: []string{"pacman", "-S", "--needed", "--noconfirm"}, sudo: true},
+	{name: "winget", command: []string{"winget", "install", "-e", "--id"}},
+	{name: "choco", command: []string{"choco", "install", "-y"}},
+	{name: "scoop", command: []string{"scoop", "install"}},
+}
+
+// installCommand returns the command which installs packages. The package names must be appended.
+func (p packageManager) installCommand() []string {
+	command := append([]string{}, p.command...)
+	if p.sudo && runtime.GOOS != "windows" && os.Geteuid() != 0 {
+		command = append([]string{"sudo"}, command...)
+	}
+	return command
+}
+
+// detectPackageManager returns the first installed package manager.
+func detectPackageManager() (packageManager, bool) {
+	for _, p := range packageManagers {
+		if _, err := exec.LookPath(p.name); err == nil {
+			return p, true
+		}
+	}
+	return packageManager{}, false
+}
+
 var installDir = "/usr/local/bin"
 
 func init() {
@@ -83,52 +314,539 @@ func init() {
 }
 
 type doctorCategory struct {
-	name  string
+	name string
+	// langs are the project languages the category is relevant for. Categories without languages are always relevant.
+	langs []string
 	rules []doctorRule
 }
 
-var doctorRules = []doctorCategory{
-	{name: "CLI", rules: []doctorRule{
-		newDoctorRuleTool("`codegame` is not in PATH. If you have installed codegame-cli in a custom install directory, make sure to add it to the PATH environment variable. Otherwise, manually add "+installDir+" to the PATH environment variable.", "codegame"),
-		newDoctorRuleToolWithCondition("Either curl or wget must be installed to use `codegame upgrade`.", runtime.GOOS != "windows", "curl", "wget"),
-	}},
-	{name: "C#", rules: []doctorRule{
-		newDoctorRuleTool("`dotnet` must be installed to develop CodeGame applications using C#. Install it from https://dotnet.microsoft.com/en-us/download.", "dotnet"),
-	}},
-	{name: "Go", rules: []doctorRule{
-		newDoctorRuleTool("`go` must be installed to develop CodeGame applications using the Go programming language. Install it from https://go.dev.", "go"),
-	}},
-	{name: "Java", rules: []doctorRule{
-		newDoctorRuleTool("`java` must be installed to develop CodeGame applications using Java. Install it from https://adoptium.net.", "java"),
-		newDoctorRuleTool("`mvn` must be installed to develop CodeGame applications using Java. Download it from https://maven.apache.org/download.cgi and follow the instructions at https://maven.apache.org/install.html.", "mvn"),
-	}},
-	{name: "JavaScript", rules: []doctorRule{
-		newDoctorRuleTool("`npm` must be installed to develop CodeGame applications using JavaScript or TypeScript. Install it from https://nodejs.org.", "npm"),
-		newDoctorRuleTool("`npx` must be installed to run TypeScript or browser based CodeGame applications. Install it using `npm install -g npx`.", "npx"),
-	}},
+// newDoctorRules returns the built-in rules. Rules cache their results and are adjusted to the current project,
+// which is why every run needs fresh values.
+func newDoctorRules() []doctorCategory {
+	return []doctorCategory{
+		{name: "CLI", rules: []doctorRule{
+			newDoctorRuleTool("`codegame` is not in PATH. If you have installed codegame-cli in a custom install directory, make sure to add it to the PATH environment variable. Otherwise, manually add "+installDir+" to the PATH environment variable.", "codegame"),
+			newDoctorRuleToolWithCondition("Either curl or wget must be installed to use `codegame upgrade`.", runtime.GOOS != "windows", "curl", "wget"),
+		}},
+		{name: "C#", langs: []string{"cs"}, rules: []doctorRule{
+			newDoctorRuleVersion("`dotnet` must be installed to develop CodeGame applications using C#. Install it from https://dotnet.microsoft.com/en-us/download.", "dotnet", []string{"--version"}, `(\d+\.\d+(?:\.\d+)?)`, "6.0", map[string]string{
+				"brew":    "--cask dotnet-sdk",
+				"apt-get": "dotnet-sdk-7.0",
+				"dnf":     "dotnet-sdk-7.0",
+				"pacman":  "dotnet-sdk",
+				"winget":  "Microsoft.DotNet.SDK.7",
+				"choco":   "dotnet-sdk",
+				"scoop":   "dotnet-sdk",
+			}),
+		}},
+		{name: "Go", langs: []string{"go"}, rules: []doctorRule{
+			newDoctorRuleVersion("`go` must be installed to develop CodeGame applications using the Go programming language. Install it from https://go.dev.", "go", []string{"version"}, `go(\d+\.\d+(?:\.\d+)?)`, "1.18", map[string]string{
+				"brew":    "go",
+				"apt-get": "golang-go",
+				"dnf":     "golang",
+				"pacman":  "go",
+				"winget":  "GoLang.Go",
+				"choco":   "golang",
+				"scoop":   "go",
+			}),
+		}},
+		{name: "Java", langs: []string{"java"}, rules: []doctorRule{
+			newDoctorRuleVersion("`java` must be installed to develop CodeGame applications using Java. Install it from https://adoptium.net.", "java", []string{"-version"}, `version "(?:1\.)?(\d+(?:\.\d+)*)`, "11", map[string]string{
+				"brew":    "openjdk@17",
+				"apt-get": "openjdk-17-jdk",
+				"dnf":     "java-17-openjdk-devel",
+				"pacman":  "jdk17-openjdk",
+				"winget":  "EclipseAdoptium.Temurin.17.JDK",
+				"choco":   "temurin17",
+				"scoop":   "temurin17-jdk",
+			}),
+			newDoctorRuleVersion("`mvn` must be installed to develop CodeGame applications using Java. Download it from https://maven.apache.org/download.cgi and follow the instructions at https://maven.apache.org/install.html.", "mvn", []string{"-v"}, `Apache Maven (\d+\.\d+(?:\.\d+)?)`, "3.6", map[string]string{
+				"brew":    "maven",
+				"apt-get": "maven",
+				"dnf":     "maven",
+				"pacman":  "maven",
+				"choco":   "maven",
+				"scoop":   "maven",
+			}),
+		}},
+		{name: "Python", langs: []string{"py"}, rules: []doctorRule{
+			newDoctorRuleVersionAlternatives("Python 3 must be installed as `python3`, `python` or `py` to develop CodeGame applications using Python. Install it from https://www.python.org/downloads.", pythonCandidates, []string{"--version"}, `Python (\d+\.\d+(?:\.\d+)?)`, "3.8", map[string]string{
+				"brew":    "python",
+				"apt-get": "python3 python3-venv",
+				"dnf":     "python3",
+				"pacman":  "python",
+				"winget":  "Python.Python.3.11",
+				"choco":   "python",
+				"scoop":   "python",
+			}),
+			newDoctorRuleVersionAlternatives("`pip` must be installed to develop CodeGame applications using Python. Install it with `python3 -m ensurepip`.", pipCommands(), []string{"--version"}, `pip (\d+\.\d+(?:\.\d+)?)`, "", map[string]string{
+				"brew":    "python",
+				"apt-get": "python3-pip",
+				"dnf":     "python3-pip",
+				"pacman":  "python-pip",
+			}),
+		}},
+		{name: "Rust", langs: []string{"rs"}, rules: []doctorRule{
+			newDoctorRuleVersion("`cargo` must be installed to develop CodeGame applications using Rust. Install it from https://rustup.rs.", "cargo", []string{"--version"}, `cargo (\d+\.\d+(?:\.\d+)?)`, "", map[string]string{
+				"brew":    "rust",
+				"apt-get": "cargo",
+				"dnf":     "cargo",
+				"pacman":  "rust",
+				"winget":  "Rustlang.Rustup",
+				"choco":   "rust",
+				"scoop":   "rustup",
+			}),
+		}},
+		{name: "JavaScript", langs: []string{"js", "ts"}, rules: []doctorRule{
+			newDoctorRuleVersion("`node` must be installed to develop CodeGame applications using JavaScript or TypeScript. Install it from https://nodejs.org.", "node", []string{"-v"}, `v(\d+\.\d+(?:\.\d+)?)`, "16", nodePackages),
+			newDoctorRuleVersion("`npm` must be installed to develop CodeGame applications using JavaScript or TypeScript. Install it from https://nodejs.org.", "npm", []string{"-v"}, `(\d+\.\d+(?:\.\d+)?)`, "", nodePackages),
+			newDoctorRuleVersion("`npx` must be installed to run TypeScript or browser based CodeGame applications. Install it using `npm install -g npx`.", "npx", []string{"-v"}, `(\d+\.\d+(?:\.\d+)?)`, "", nil),
+		}},
+	}
+}
+
+// pipCommands returns the commands under which pip might be available: `pip`, `pip3` or the pip module of one of pythonCandidates.
+func pipCommands() [][]string {
+	commands := [][]string{{"pip"}, {"pip3"}}
+	for _, python := range pythonCandidates {
+		commands = append(commands, append(append([]string{}, python...), "-m", "pip"))
+	}
+	return commands
+}
+
+var nodePackages = map[string]string{
+	"brew":    "node",
+	"apt-get": "nodejs npm",
+	"dnf":     "nodejs",
+	"pacman":  "nodejs npm",
+	"winget":  "OpenJS.NodeJS.LTS",
+	"choco":   "nodejs-lts",
+	"scoop":   "nodejs-lts",
 }
 
 // doctorCmd represents the doctor command
 var doctorCmd = &cobra.Command{
 	Use:   "doctor",
 	Short: "Check for missing dependencies and misconfigurations.",
-	Run: func(_ *cobra.Command, _ []string) {
-		for _, category := range doctorRules {
-			cli.PrintColor(cli.Cyan, "%s:", category.name)
-			for _, r := range category.rules {
-				if _, ok := r.(doctorRuleInactive); ok {
-					continue
+	Long: `Check for missing dependencies and misconfigurations.
+
+Inside of a project only the tools of the project language are checked. Additionally the project file,
+the connection to the game server, the generated event definitions and the dev port are checked.
+
+Outside of a project missing language tools are only reported as warnings, because the language might not be used.
+Failed checks, which are not warnings, make doctor exit with a non-zero exit code.
+
+Additional rules can be declared in doctor.json in the CodeGame config directory and by plugins
+in '<name>.doctor.json' files in the plugin directory:
+
+  {
+    "rules": [
+      {
+        "name": "Docker",
+        "category": "Tools",
+        "command": ["docker", "--version"],
+        "expect": "Docker version ([0-9.]+)",
+        "severity": "warning",
+        "message": "Docker is not installed.",
+        "fix": "Install Docker from https://docs.docker.com/get-docker.",
+        "langs": ["go"]
+      }
+    ]
+  }
+
+The severity is one of error (default), warning or info. Only errors make doctor fail.`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		fix, err := cmd.Flags().GetBool("fix")
+		abort(err)
+		all, err := cmd.Flags().GetBool("all")
+		abort(err)
+
+		var categories []doctorCategory
+		if root, err := cgfile.FindProjectRoot(); err == nil && !all {
+			categories = projectDoctorCategories(root)
+		} else {
+			categories = mergeDoctorCategories(newDoctorRules(), customDoctorCategories(""))
+		}
+
+		report := runDoctorRules(cmd, categories)
+
+		if fix {
+			fixDoctorRules(report.fixable)
+		}
+
+		if !report.OK {
+			os.Exit(1)
+		}
+	},
+}
+
+var supportedLanguages = map[string][]string{
+	"client": {"cs", "go", "java", "js", "py", "rs", "ts"},
+	"server": {"go", "java", "ts"},
+}
+
+var javaPackageRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)*$`)
+
+// projectDoctorCategories returns the checks for the project at root and the tools of its language.
+func projectDoctorCategories(root string) []doctorCategory {
+	data, err := cgfile.LoadCodeGameFile(root)
+	if err != nil {
+		return append([]doctorCategory{{name: "Project", rules: []doctorRule{
+			newDoctorRuleCheck(true, func() (string, error) {
+				return "", fmt.Errorf("Failed to load .codegame.json: %s", err)
+			}),
+		}}}, mergeDoctorCategories(newDoctorRules(), customDoctorCategories(""))...)
+	}
+
+	var api *server.API
+	var info server.GameInfo
+	project := doctorCategory{name: "Project", rules: []doctorRule{
+		newDoctorRuleCheck(true, func() (string, error) {
+			languages, ok := supportedLanguages[data.Type]
+			if !ok {
+				return "", fmt.Errorf("Unknown project type `%s` in .codegame.json.", data.Type)
+			}
+			if !containsString(languages, data.Lang) {
+				return "", fmt.Errorf("Unsupported language `%s` for %s projects in .codegame.json.", data.Lang, data.Type)
+			}
+			if data.Game == "" {
+				return "", errors.New("Missing game name in .codegame.json.")
+			}
+			if data.Type == "client" && data.URL == "" {
+				return "", errors.New("Missing game URL in .codegame.json.")
+			}
+			return fmt.Sprintf("`.codegame.json` describes a %s %s for `%s`.", data.Lang, data.Type, data.Game), nil
+		}),
+		newDoctorRuleCheck(true, func() (string, error) {
+			if data.Lang != "java" {
+				return "", nil
+			}
+			_, err := eventsOutputDir(data.Lang, data.Game, data.LangConfig)
+			if err != nil {
+				return "", err
+			}
+			packageName := data.LangConfig["package"].(string)
+			if !javaPackageRegex.MatchString(packageName) {
+				return "", fmt.Errorf("`%s` in .codegame.json is not a valid Java package name.", packageName)
+			}
+			return fmt.Sprintf("The Java package `%s` is valid.", packageName), nil
+		}),
+		newDoctorRuleCheck(true, func() (string, error) {
+			if data.Type != "client" || data.URL == "" {
+				return "", nil
+			}
+			var err error
+			api, err = server.NewAPI(external.TrimURL(data.URL))
+			if err == nil {
+				info, err = api.FetchGameInfo()
+			}
+			if err != nil {
+				api = nil
+				return "", fmt.Errorf("The game server at `%s` is not reachable: %s", data.URL, err)
+			}
+			return fmt.Sprintf("The game server at `%s` is reachable.", data.URL), nil
+		}),
+		newDoctorRuleCheck(false, func() (string, error) {
+			if api == nil || data.GameVersion == "" || info.Version == "" {
+				return "", nil
+			}
+			if gameVersionMismatch(data.GameVersion, info.Version) {
+				return "", fmt.Errorf("The project was created for v%s of the game, but the server runs v%s. Run `codegame update`.", data.GameVersion, info.Version)
+			}
+			return fmt.Sprintf("The project matches the game version of the server (v%s).", info.Version), nil
+		}),
+		newDoctorRuleCheck(true, func() (string, error) {
+			var cgePath, cge string
+			switch {
+			case data.Type == "server":
+				cgePath = filepath.Join(root, "events.cge")
+				content, err := os.ReadFile(cgePath)
+				if err != nil {
+					return "", fmt.Errorf("Failed to read events.cge: %s", err)
 				}
-				if r.Check() {
-					cli.PrintColor(cli.Green, "  √ %s", r.SuccessMessage())
-				} else {
-					cli.PrintColor(cli.Red, "  x %s", r.ErrMessage())
+				cge = string(content)
+			case data.Type == "client" && api != nil:
+				var err error
+				cge, err = api.GetCGEFile()
+				if err != nil {
+					return "", fmt.Errorf("Failed to fetch the CGE file of the game server: %s", err)
 				}
+				cgePath = api.BaseURL()
+			default:
+				return "", nil
 			}
+			return checkEventsUpToDate(root, data, cgePath, cge)
+		}),
+		newDoctorRuleCheck(false, func() (string, error) {
+			port := config.Load().DevPort
+			listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+			if err != nil {
+				return "", fmt.Errorf("The dev port %d is already in use. `codegame run` will use port %d instead.", port, findAvailablePort(port))
+			}
+			listener.Close()
+			return fmt.Sprintf("The dev port %d is available.", port), nil
+		}),
+	}}
+
+	categories := []doctorCategory{project}
+	for _, category := range mergeDoctorCategories(newDoctorRules(), customDoctorCategories(data.Lang)) {
+		if len(category.langs) == 0 {
+			categories = append(categories, category)
+			continue
 		}
-	},
+		if !containsString(category.langs, data.Lang) {
+			continue
+		}
+		for _, r := range category.rules {
+			if v, ok := r.(*doctorRuleVersion); ok {
+				v.required = true
+			}
+		}
+		categories = append(categories, category)
+	}
+	return categories
+}
+
+// checkEventsUpToDate generates the event definitions of the project from the CGE file at cgePath into a temporary directory
+// and compares them with the event definitions in the project.
+func checkEventsUpToDate(root string, data *cgfile.CodeGameFileData, cgePath, cge string) (string, error) {
+	eventsOutput, err := eventsOutputDir(data.Lang, data.Game, data.LangConfig)
+	if err != nil || eventsOutput == "" {
+		return "", err
+	}
+	cgeVersion, err := cggenevents.ParseCGEVersion(cge)
+	if err != nil {
+		return "", err
+	}
+
+	tmp, err := os.MkdirTemp("", "codegame-cli-events-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+
+	err = cggenevents.CGGenEvents(cgeVersion, filepath.Join(tmp, eventsOutput), cgePath, data.Lang)
+	if err != nil {
+		return "", fmt.Errorf("Failed to generate event definitions: %s", err)
+	}
+
+	outdated := errors.New("The event definitions in `" + eventsOutput + "` are out of date. Run `codegame update`.")
+	if data.Type == "server" {
+		outdated = errors.New("The event definitions in `" + eventsOutput + "` are out of date. Run `codegame gen-events`.")
+	}
+	err = filepath.WalkDir(filepath.Join(tmp, eventsOutput), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(tmp, path)
+		if err != nil {
+			return err
+		}
+		generated, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		existing, err := os.ReadFile(filepath.Join(root, rel))
+		if err != nil || !bytes.Equal(generated, existing) {
+			return outdated
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("The event definitions in `%s` are up to date.", eventsOutput), nil
+}
+
+type doctorSeverity string
+
+const (
+	severityError   doctorSeverity = "error"
+	severityWarning doctorSeverity = "warning"
+	severityInfo    doctorSeverity = "info"
+)
+
+// doctorRuleSeverity is implemented by rules which specify their severity explicitly instead of with Required.
+type doctorRuleSeverity interface {
+	Severity() doctorSeverity
+}
+
+// doctorRuleHint is implemented by rules which describe how to fix a failure.
+type doctorRuleHint interface {
+	FixHint() string
+}
+
+// severityOf returns the severity of a failure of r. It must be called after r.Check().
+func severityOf(r doctorRule) doctorSeverity {
+	if s, ok := r.(doctorRuleSeverity); ok {
+		return s.Severity()
+	}
+	if r.Required() {
+		return severityError
+	}
+	return severityWarning
+}
+
+type doctorResult struct {
+	// Status is either "ok" or "failed".
+	Status   string         `json:"status"`
+	Severity doctorSeverity `json:"severity"`
+	Message  string         `json:"message"`
+	Fix      string         `json:"fix,omitempty"`
+}
+
+type doctorCategoryReport struct {
+	Name    string         `json:"name"`
+	Results []doctorResult `json:"results"`
+}
+
+type doctorReport struct {
+	// OK is false if a rule with the severity error failed.
+	OK         bool                   `json:"ok"`
+	Categories []doctorCategoryReport `json:"categories"`
+
+	fixable []doctorRule
+}
+
+// checkDoctorRules checks all rules of categories.
+func checkDoctorRules(categories []doctorCategory) doctorReport {
+	report := doctorReport{
+		OK:         true,
+		Categories: make([]doctorCategoryReport, 0, len(categories)),
+		fixable:    make([]doctorRule, 0),
+	}
+	manager, hasManager := detectPackageManager()
+	for _, category := range categories {
+		categoryReport := doctorCategoryReport{
+			Name:    category.name,
+			Results: make([]doctorResult, 0, len(category.rules)),
+		}
+		for _, r := range category.rules {
+			if _, ok := r.(doctorRuleInactive); ok {
+				continue
+			}
+			if r.Check() {
+				if message := r.SuccessMessage(); message != "" {
+					categoryReport.Results = append(categoryReport.Results, doctorResult{
+						Status:   "ok",
+						Severity: severityOf(r),
+						Message:  message,
+					})
+				}
+				continue
+			}
+			result := doctorResult{
+				Status:   "failed",
+				Severity: severityOf(r),
+				Message:  r.ErrMessage(),
+			}
+			if result.Severity == severityError {
+				report.OK = false
+			}
+			if h, ok := r.(doctorRuleHint); ok {
+				result.Fix = h.FixHint()
+			}
+			if f, ok := r.(doctorRuleFixable); ok {
+				report.fixable = append(report.fixable, r)
+				if command := f.FixCommand(manager); hasManager && command != nil && result.Fix == "" {
+					result.Fix = strings.Join(command, " ")
+				}
+			}
+			categoryReport.Results = append(categoryReport.Results, result)
+		}
+		report.Categories = append(report.Categories, categoryReport)
+	}
+	return report
+}
+
+func (r doctorReport) print() {
+	for _, category := range r.Categories {
+		cli.PrintColor(cli.Cyan, "%s:", category.Name)
+		for _, result := range category.Results {
+			if result.Status == "ok" {
+				cli.PrintColor(cli.Green, "  √ %s", result.Message)
+				continue
+			}
+			switch result.Severity {
+			case severityError:
+				cli.PrintColor(cli.Red, "  x %s", result.Message)
+			case severityWarning:
+				cli.PrintColor(cli.Yellow, "  ! %s", result.Message)
+			default:
+				cli.Print("  i %s", result.Message)
+			}
+			if result.Fix != "" {
+				cli.Print("    Fix: %s", result.Fix)
+			}
+		}
+	}
+}
+
+// runDoctorRules checks all rules of categories and prints the report as text or, with the --json flag, as JSON.
+func runDoctorRules(cmd *cobra.Command, categories []doctorCategory) doctorReport {
+	jsonOutput, err := cmd.Flags().GetBool("json")
+	abort(err)
+
+	report := checkDoctorRules(categories)
+	if jsonOutput {
+		data, err := json.MarshalIndent(report, "", "  ")
+		abort(err)
+		fmt.Println(string(data))
+	} else {
+		report.print()
+	}
+	return report
+}
+
+// fixDoctorRules offers to install the missing packages of rules with the detected package manager.
+func fixDoctorRules(rules []doctorRule) {
+	if len(rules) == 0 {
+		return
+	}
+	manager, ok := detectPackageManager()
+	if !ok {
+		cli.Warn("No supported package manager found. Please install the missing tools manually.")
+		return
+	}
+
+	commands := make([][]string, 0, len(rules))
+	seen := make(map[string]bool)
+	for _, r := range rules {
+		command := r.(doctorRuleFixable).FixCommand(manager)
+		if command == nil || seen[strings.Join(command, " ")] {
+			continue
+		}
+		seen[strings.Join(command, " ")] = true
+		commands = append(commands, command)
+	}
+	if len(commands) == 0 {
+		cli.Warn("The missing tools are not available with %s. Please install them manually.", manager.name)
+		return
+	}
+
+	fixed := false
+	for _, command := range commands {
+		yes, err := cli.YesNo(fmt.Sprintf("Run `%s`?", strings.Join(command, " ")), true)
+		abort(err)
+		if !yes {
+			continue
+		}
+		_, err = cgExec.Execute(false, command[0], command[1:]...)
+		if err != nil {
+			cli.Error(err.Error())
+			continue
+		}
+		fixed = true
+	}
+	if fixed {
+		cli.Success("Run `codegame doctor` again to verify the installation.")
+	}
 }
 
 func init() {
 	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().BoolP("fix", "", false, "Offer to install missing tools with a detected package manager.")
+	doctorCmd.Flags().BoolP("all", "a", false, "Check the tools of all languages instead of the current project.")
+	doctorCmd.PersistentFlags().BoolP("json", "", false, "Print the report as JSON.")
+	doctorCmd.MarkFlagsMutuallyExclusive("fix", "json")
 }
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adrg/xdg"
+)
+
+// doctorRulesPath is the path of the file declaring custom doctor rules.
+var doctorRulesPath = filepath.Join(xdg.ConfigHome, "codegame", "doctor.json")
+
+// pluginsDir contains installed plugins. Plugins can declare doctor rules in `<name>.doctor.json` files.
+var pluginsDir = filepath.Join(xdg.DataHome, "codegame", "plugins")
+
+// doctorRulesFile is the format of doctor.json and the `*.doctor.json` files of plugins.
+type doctorRulesFile struct {
+	Rules []doctorRuleConfig `json:"rules"`
+}
+
+type doctorRuleConfig struct {
+	// Name is shown in the success message.
+	Name string `json:"name"`
+	// Category is the name of the category the rule is added to. Default: Custom
+	Category string `json:"category"`
+	// Command is the program and its arguments. The rule fails if it cannot be executed or exits with a non-zero status.
+	Command []string `json:"command"`
+	// Expect is a regular expression, which must match the combined output of the command.
+	// The first submatch is included in the success message.
+	Expect string `json:"expect"`
+	// Severity is one of error, warning or info. Default: error
+	Severity doctorSeverity `json:"severity"`
+	// Message is shown if the rule fails.
+	Message string `json:"message"`
+	// Fix describes how to fix a failure.
+	Fix string `json:"fix"`
+	// Langs restricts the rule to projects of these languages. Rules without languages are always checked.
+	Langs []string `json:"langs"`
+}
+
+// doctorRuleCommand checks the exit status and output of a command declared in a doctor rules file.
+type doctorRuleCommand struct {
+	config  doctorRuleConfig
+	pattern *regexp.Regexp
+
+	match string
+	err   error
+}
+
+func newDoctorRuleCommand(config doctorRuleConfig) (doctorRule, error) {
+	if len(config.Command) == 0 {
+		return nil, fmt.Errorf("rule '%s' has no command", config.Name)
+	}
+	if config.Name == "" {
+		config.Name = config.Command[0]
+	}
+	switch config.Severity {
+	case "":
+		config.Severity = severityError
+	case severityError, severityWarning, severityInfo:
+	default:
+		return nil, fmt.Errorf("rule '%s' has an invalid severity '%s'", config.Name, config.Severity)
+	}
+	rule := &doctorRuleCommand{
+		config: config,
+	}
+	if config.Expect != "" {
+		var err error
+		rule.pattern, err = regexp.Compile(config.Expect)
+		if err != nil {
+			return nil, fmt.Errorf("rule '%s' has an invalid expect pattern: %w", config.Name, err)
+		}
+	}
+	return rule, nil
+}
+
+func (d *doctorRuleCommand) Check() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, d.config.Command[0], d.config.Command[1:]...).CombinedOutput()
+	if err != nil {
+		d.err = err
+		return false
+	}
+	if d.pattern != nil {
+		match := d.pattern.FindSubmatch(out)
+		if match == nil {
+			d.err = fmt.Errorf("the output does not match `%s`", d.config.Expect)
+			return false
+		}
+		if len(match) > 1 {
+			d.match = string(match[1])
+		}
+	}
+	return true
+}
+
+func (d *doctorRuleCommand) ErrMessage() string {
+	if d.config.Message != "" {
+		return d.config.Message
+	}
+	return fmt.Sprintf("%s: `%s` failed: %s", d.config.Name, strings.Join(d.config.Command, " "), d.err)
+}
+
+func (d *doctorRuleCommand) SuccessMessage() string {
+	if d.match != "" {
+		return fmt.Sprintf("%s: %s", d.config.Name, d.match)
+	}
+	return fmt.Sprintf("%s: ok", d.config.Name)
+}
+
+func (d *doctorRuleCommand) Required() bool {
+	return d.config.Severity == severityError
+}
+
+func (d *doctorRuleCommand) Severity() doctorSeverity {
+	return d.config.Severity
+}
+
+func (d *doctorRuleCommand) FixHint() string {
+	return d.config.Fix
+}
+
+// customDoctorCategories loads the rules declared in doctor.json and by plugins.
+// Rules restricted to other languages than lang are skipped unless lang is empty.
+// Invalid files are reported as failing rules.
+func customDoctorCategories(lang string) []doctorCategory {
+	paths := []string{doctorRulesPath}
+	if pluginFiles, err := filepath.Glob(filepath.Join(pluginsDir, "*.doctor.json")); err == nil {
+		sort.Strings(pluginFiles)
+		paths = append(paths, pluginFiles...)
+	}
+
+	categories := make([]doctorCategory, 0)
+	add := func(name string, rule doctorRule) {
+		for i := range categories {
+			if categories[i].name == name {
+				categories[i].rules = append(categories[i].rules, rule)
+				return
+			}
+		}
+		categories = append(categories, doctorCategory{name: name, rules: []doctorRule{rule}})
+	}
+
+	for _, path := range paths {
+		configs, err := loadDoctorRulesFile(path)
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				path := path
+				add("Custom", newDoctorRuleCheck(true, func() (string, error) {
+					return "", fmt.Errorf("Failed to load doctor rules from `%s`: %s", path, err)
+				}))
+			}
+			continue
+		}
+		for _, config := range configs {
+			if lang != "" && len(config.Langs) > 0 && !containsString(config.Langs, lang) {
+				continue
+			}
+			category := config.Category
+			if category == "" {
+				category = "Custom"
+			}
+			rule, err := newDoctorRuleCommand(config)
+			if err != nil {
+				path := path
+				rule = newDoctorRuleCheck(true, func() (string, error) {
+					return "", fmt.Errorf("Invalid doctor rule in `%s`: %s", path, err)
+				})
+			}
+			add(category, rule)
+		}
+	}
+	return categories
+}
+
+func loadDoctorRulesFile(path string) ([]doctorRuleConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file doctorRulesFile
+	err = json.Unmarshal(data, &file)
+	if err != nil {
+		return nil, err
+	}
+	return file.Rules, nil
+}
+
+// mergeDoctorCategories appends the rules of extra to the categories of base with the same name and adds the remaining categories.
+// base is not modified.
+func mergeDoctorCategories(base, extra []doctorCategory) []doctorCategory {
+	merged := make([]doctorCategory, len(base))
+	copy(merged, base)
+	for _, e := range extra {
+		found := false
+		for i := range merged {
+			if merged[i].name == e.name {
+				rules := make([]doctorRule, 0, len(merged[i].rules)+len(e.rules))
+				merged[i].rules = append(append(rules, merged[i].rules...), e.rules...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, e)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,394 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Bananenpro/cli"
+	"github.com/code-game-project/go-utils/cggenevents"
+	"github.com/code-game-project/go-utils/external"
+	"github.com/code-game-project/go-utils/server"
+	"github.com/gorilla/websocket"
+	"github.com/spf13/cobra"
+)
+
+// doctorNetworkCmd represents the doctor network command
+var doctorNetworkCmd = &cobra.Command{
+	Use:   "network [url]",
+	Short: "Diagnose the connection to a game server and CodeGame Share.",
+	Long: `Diagnose the connection to a game server and CodeGame Share.
+
+Checks DNS resolution, TCP and TLS connectivity, whether the CLI will use HTTP or HTTPS,
+the /api/info and /api/events endpoints, websocket upgrades, the latency, the proxy settings
+and the connection to the configured CodeGame Share server.`,
+	Args:              cobra.RangeArgs(0, 1),
+	ValidArgsFunction: completeGameURL,
+	Run: func(cmd *cobra.Command, args []string) {
+		var gameURL string
+		if len(args) > 0 {
+			gameURL = args[0]
+		} else if gameURL = findGameURL(); gameURL != "" {
+			cli.Print("Game URL: %s", gameURL)
+		} else {
+			var err error
+			gameURL, err = cli.Input("Game URL:")
+			abort(err)
+		}
+
+		game := newNetworkTarget(gameURL)
+		shareTarget := newNetworkTarget(newShareClient("").BaseURL())
+
+		gameRules := networkHostRules(game)
+		gameRules = append(gameRules, networkGameServerRules(game)...)
+
+		shareRules := networkHostRules(shareTarget)
+		shareRules = append(shareRules, newDoctorRuleCheck(true, func() (string, error) {
+			if !shareTarget.reachable {
+				return "", nil
+			}
+			start := time.Now()
+			resp, err := networkHTTPClient.Get(shareTarget.baseURL)
+			if err != nil {
+				return "", fmt.Errorf("CodeGame Share at `%s` is not reachable: %s", shareTarget.baseURL, err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode >= 500 {
+				return "", fmt.Errorf("CodeGame Share at `%s` responded with %s.", shareTarget.baseURL, resp.Status)
+			}
+			return fmt.Sprintf("CodeGame Share at `%s` responded in %s.", shareTarget.baseURL, formatLatency(time.Since(start))), nil
+		}))
+
+		report := runDoctorRules(cmd, []doctorCategory{
+			{name: "Game server", rules: gameRules},
+			{name: "Proxy", rules: networkProxyRules(game, shareTarget)},
+			{name: "CodeGame Share", rules: shareRules},
+		})
+		if !report.OK {
+			os.Exit(1)
+		}
+	},
+}
+
+var networkHTTPClient = &http.Client{
+	Timeout: 10 * time.Second,
+}
+
+// networkTarget holds the state of the network checks of a single server, which later checks depend on.
+type networkTarget struct {
+	// url is the URL without the protocol.
+	url string
+	// scheme is the protocol explicitly included in the URL or empty if the CLI has to guess it.
+	scheme string
+	host   string
+	port   string
+
+	// reachable is true if a TCP connection could be established.
+	reachable bool
+	// baseURL is the URL including the protocol the CLI will use.
+	baseURL string
+}
+
+func newNetworkTarget(rawURL string) *networkTarget {
+	target := &networkTarget{
+		url: external.TrimURL(rawURL),
+	}
+	if strings.HasPrefix(rawURL, "http://") || strings.HasPrefix(rawURL, "https://") {
+		target.scheme = strings.SplitN(rawURL, "://", 2)[0]
+	}
+	if u, err := url.Parse("http://" + target.url); err == nil {
+		target.host = u.Hostname()
+		target.port = u.Port()
+	}
+	return target
+}
+
+// ports returns the ports the server can be reached with.
+func (n *networkTarget) ports() []string {
+	switch {
+	case n.port != "":
+		return []string{n.port}
+	case n.scheme == "https":
+		return []string{"443"}
+	case n.scheme == "http":
+		return []string{"80"}
+	default:
+		return []string{"443", "80"}
+	}
+}
+
+// networkHostRules returns the rules checking DNS resolution, TCP connectivity and TLS support of target.
+func networkHostRules(target *networkTarget) []doctorRule {
+	resolved := false
+	return []doctorRule{
+		newDoctorRuleCheck(true, func() (string, error) {
+			if target.host == "" {
+				return "", fmt.Errorf("`%s` is not a valid URL.", target.url)
+			}
+			if net.ParseIP(target.host) != nil {
+				resolved = true
+				return fmt.Sprintf("`%s` is an IP address and does not need to be resolved.", target.host), nil
+			}
+			addrs, err := net.LookupHost(target.host)
+			if err != nil {
+				return "", fmt.Errorf("Failed to resolve `%s`: %s", target.host, err)
+			}
+			resolved = true
+			return fmt.Sprintf("`%s` resolves to %s.", target.host, strings.Join(addrs, ", ")), nil
+		}),
+		newDoctorRuleCheck(true, func() (string, error) {
+			if !resolved {
+				return "", nil
+			}
+			open := make([]string, 0)
+			closed := make([]string, 0)
+			var lastErr error
+			for _, port := range target.ports() {
+				conn, err := net.DialTimeout("tcp", net.JoinHostPort(target.host, port), 5*time.Second)
+				if err != nil {
+					closed = append(closed, port)
+					lastErr = err
+					continue
+				}
+				conn.Close()
+				open = append(open, port)
+			}
+			if len(open) == 0 {
+				return "", fmt.Errorf("Failed to open a TCP connection to `%s` on port %s: %s", target.host, strings.Join(closed, " or "), lastErr)
+			}
+			target.reachable = true
+			if len(closed) > 0 {
+				return fmt.Sprintf("TCP connection to `%s` on port %s succeeded (port %s is closed).", target.host, strings.Join(open, ", "), strings.Join(closed, ", ")), nil
+			}
+			return fmt.Sprintf("TCP connection to `%s` on port %s succeeded.", target.host, strings.Join(open, " and ")), nil
+		}),
+		newDoctorRuleCheck(false, func() (string, error) {
+			if !target.reachable {
+				return "", nil
+			}
+			if target.scheme != "" {
+				target.baseURL = target.scheme + "://" + target.url
+				return fmt.Sprintf("The URL explicitly uses %s.", strings.ToUpper(target.scheme)), nil
+			}
+			isTLS := external.IsTLS(target.url)
+			target.baseURL = external.BaseURL("http", isTLS, target.url)
+			if isTLS {
+				return "The TLS certificate is valid. The CLI will use HTTPS and WSS.", nil
+			}
+			return "", fmt.Errorf("TLS is not available (%s). The CLI will fall back to unencrypted HTTP and WS.", tlsFailureReason(target))
+		}),
+	}
+}
+
+// tlsFailureReason explains why external.IsTLS does not consider the server to support TLS.
+func tlsFailureReason(target *networkTarget) string {
+	port := target.port
+	if port == "" {
+		port = "443"
+	}
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", net.JoinHostPort(target.host, port), &tls.Config{})
+	if err != nil {
+		var recordErr tls.RecordHeaderError
+		if errors.As(err, &recordErr) {
+			return fmt.Sprintf("port %s does not speak TLS", port)
+		}
+		return err.Error()
+	}
+	defer conn.Close()
+	if err = conn.VerifyHostname(target.host); err != nil {
+		return err.Error()
+	}
+	expiry := conn.ConnectionState().PeerCertificates[0].NotAfter
+	if time.Now().After(expiry) {
+		return fmt.Sprintf("the certificate expired on %s", expiry.Format("2006-01-02"))
+	}
+	return "the TLS handshake timed out"
+}
+
+// networkGameServerRules returns the rules checking the CodeGame API of the game server at target.
+func networkGameServerRules(target *networkTarget) []doctorRule {
+	var apiURL string
+	return []doctorRule{
+		newDoctorRuleCheck(true, func() (string, error) {
+			if target.baseURL == "" {
+				return "", nil
+			}
+			apiURL = target.baseURL + "/api"
+			start := time.Now()
+			resp, err := networkHTTPClient.Get(apiURL + "/info")
+			if err != nil {
+				apiURL = ""
+				return "", fmt.Errorf("GET /api/info failed: %s", err)
+			}
+			defer resp.Body.Close()
+			duration := time.Since(start)
+			if resp.StatusCode != http.StatusOK {
+				apiURL = ""
+				return "", fmt.Errorf("GET /api/info responded with %s. Is `%s` a CodeGame game server?", resp.Status, target.url)
+			}
+			if !external.HasContentType(resp.Header, "application/json") {
+				apiURL = ""
+				return "", fmt.Errorf("GET /api/info responded with the content type `%s` instead of `application/json`.", resp.Header.Get("Content-Type"))
+			}
+			var info server.GameInfo
+			err = json.NewDecoder(resp.Body).Decode(&info)
+			if err != nil || info.Name == "" || info.CGVersion == "" {
+				apiURL = ""
+				return "", errors.New("GET /api/info responded with invalid game info.")
+			}
+			return fmt.Sprintf("GET /api/info responded in %s: %s v%s (CodeGame v%s).", formatLatency(duration), info.Name, info.Version, info.CGVersion), nil
+		}),
+		newDoctorRuleCheck(true, func() (string, error) {
+			if apiURL == "" {
+				return "", nil
+			}
+			resp, err := networkHTTPClient.Get(apiURL + "/events")
+			if err != nil {
+				return "", fmt.Errorf("GET /api/events failed: %s", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return "", fmt.Errorf("GET /api/events responded with %s.", resp.Status)
+			}
+			if !external.HasContentType(resp.Header, "text/plain") {
+				return "", fmt.Errorf("GET /api/events responded with the content type `%s` instead of `text/plain`.", resp.Header.Get("Content-Type"))
+			}
+			content, err := io.ReadAll(resp.Body)
+			if err != nil {
+				return "", fmt.Errorf("Failed to read the response of GET /api/events: %s", err)
+			}
+			version, err := cggenevents.ParseCGEVersion(string(content))
+			if err != nil {
+				return "", fmt.Errorf("GET /api/events did not return a valid CGE file: %s", err)
+			}
+			return fmt.Sprintf("GET /api/events returned a CGE v%s file (%d bytes).", version, len(content)), nil
+		}),
+		newDoctorRuleCheck(true, func() (string, error) {
+			if apiURL == "" {
+				return "", nil
+			}
+			gameId := "00000000-0000-0000-0000-000000000000"
+			hasGame := false
+			if resp, err := networkHTTPClient.Get(apiURL + "/games"); err == nil {
+				var games struct {
+					Public []server.GameListEntry `json:"public"`
+				}
+				if json.NewDecoder(resp.Body).Decode(&games) == nil && len(games.Public) > 0 {
+					gameId = games.Public[0].Id
+					hasGame = true
+				}
+				resp.Body.Close()
+			}
+			wsURL := "ws" + strings.TrimPrefix(apiURL, "http") + "/games/" + gameId + "/spectate"
+			conn, resp, err := websocket.DefaultDialer.Dial(wsURL, nil)
+			if err == nil {
+				conn.Close()
+				return fmt.Sprintf("Websocket upgrade succeeded (spectated public game %s).", gameId), nil
+			}
+			if resp == nil {
+				return "", fmt.Errorf("Websocket connection to %s failed: %s", wsURL, err)
+			}
+			if !hasGame && resp.StatusCode == http.StatusNotFound {
+				return "The websocket endpoint is reachable, but there is no public game to complete an upgrade with.", nil
+			}
+			return "", fmt.Errorf("Websocket upgrade failed with %s. A proxy or firewall might be blocking websocket connections.", resp.Status)
+		}),
+		newDoctorRuleCheck(false, func() (string, error) {
+			if apiURL == "" {
+				return "", nil
+			}
+			const count = 5
+			var total, slowest time.Duration
+			fastest := time.Duration(-1)
+			for i := 0; i < count; i++ {
+				start := time.Now()
+				resp, err := networkHTTPClient.Get(apiURL + "/info")
+				if err != nil {
+					return "", fmt.Errorf("Latency measurement failed: %s", err)
+				}
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+				duration := time.Since(start)
+				total += duration
+				if duration > slowest {
+					slowest = duration
+				}
+				if fastest < 0 || duration < fastest {
+					fastest = duration
+				}
+			}
+			average := total / count
+			message := fmt.Sprintf("Latency over %d requests: avg %s, min %s, max %s", count, formatLatency(average), formatLatency(fastest), formatLatency(slowest))
+			if average > 500*time.Millisecond {
+				return "", fmt.Errorf("%s. The connection is slow.", message)
+			}
+			return message + ".", nil
+		}),
+	}
+}
+
+// networkProxyRules returns the rules describing the proxy settings used for the game server and the Share server.
+func networkProxyRules(game, shareTarget *networkTarget) []doctorRule {
+	rules := []doctorRule{
+		newDoctorRuleCheck(false, func() (string, error) {
+			settings := make([]string, 0)
+			for _, name := range []string{"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "CG_SHARE_PROXY"} {
+				value := os.Getenv(name)
+				if value == "" {
+					value = os.Getenv(strings.ToLower(name))
+				}
+				if value != "" {
+					settings = append(settings, fmt.Sprintf("%s=%s", name, value))
+				}
+			}
+			if len(settings) == 0 {
+				return "No proxy is configured.", nil
+			}
+			return "Proxy settings: " + strings.Join(settings, ", "), nil
+		}),
+	}
+	for _, target := range []*networkTarget{game, shareTarget} {
+		target := target
+		rules = append(rules, newDoctorRuleCheck(false, func() (string, error) {
+			if target.baseURL == "" {
+				return "", nil
+			}
+			proxy := os.Getenv("CG_SHARE_PROXY")
+			if target != shareTarget || proxy == "" {
+				request, err := http.NewRequest(http.MethodGet, target.baseURL, nil)
+				if err != nil {
+					return "", err
+				}
+				proxyURL, err := http.ProxyFromEnvironment(request)
+				if err != nil {
+					return "", fmt.Errorf("Invalid proxy configuration: %s", err)
+				}
+				if proxyURL == nil {
+					return fmt.Sprintf("Requests to `%s` are sent directly.", target.host), nil
+				}
+				proxy = proxyURL.String()
+			}
+			return fmt.Sprintf("Requests to `%s` are sent through the proxy `%s`.", target.host, proxy), nil
+		}))
+	}
+	return rules
+}
+
+func formatLatency(duration time.Duration) string {
+	if duration < time.Millisecond {
+		return duration.Round(time.Microsecond).String()
+	}
+	return duration.Round(time.Millisecond).String()
+}
+
+func init() {
+	doctorCmd.AddCommand(doctorNetworkCmd)
+}
@@ -1,6 +1,15 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Bananenpro/cli"
+	"github.com/code-game-project/go-utils/server"
+	"github.com/code-game-project/go-utils/sessions"
+	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 )
 
@@ -10,6 +19,103 @@ var gameCmd = &cobra.Command{
 	Short: "Manage CodeGame games.",
 }
 
+// selectGame determines the game URL and game ID from args, the current project, the stored sessions or user input.
+// A single argument is interpreted as the game ID if it is a UUID and the current project has a game URL.
+func selectGame(args []string) (gameURL string, gameId string, err error) {
+	cgConfURL := findGameURL()
+
+	if len(args) > 0 {
+		gameURL = args[0]
+		if len(args) > 1 {
+			gameId = args[1]
+		} else if _, err := uuid.Parse(gameURL); err == nil && cgConfURL != "" {
+			gameId = gameURL
+			gameURL = ""
+		}
+	}
+
+	if gameURL == "" {
+		if cgConfURL != "" {
+			gameURL = cgConfURL
+			cli.Print("Game URL: %s", gameURL)
+		} else if urls, _ := sessions.ListGames(); len(urls) > 0 {
+			fromSession, err := cli.YesNo("Select game URL from session?", true)
+			if err != nil {
+				return "", "", err
+			}
+			if fromSession {
+				selected, err := cli.Select("Game URL:", urls)
+				if err != nil {
+					return "", "", err
+				}
+				gameURL = urls[selected]
+				cli.Print("Game URL: %s", gameURL)
+			}
+		}
+		if gameURL == "" {
+			gameURL, err = cli.Input("Game URL:")
+			if err != nil {
+				return "", "", err
+			}
+		}
+	}
+
+	if gameId == "" {
+		if usernames, _ := sessions.ListUsernames(gameURL); len(usernames) > 0 {
+			fromSession, err := cli.YesNo("Select game ID from session?", true)
+			if err != nil {
+				return "", "", err
+			}
+			if fromSession {
+				selected, err := cli.Select("Username:", usernames)
+				if err != nil {
+					return "", "", err
+				}
+				session, err := sessions.LoadSession(gameURL, usernames[selected])
+				if err != nil {
+					return "", "", fmt.Errorf("Failed to load session: %w", err)
+				}
+				gameId = session.GameId
+				cli.Print("Game ID: %s", gameId)
+			}
+		}
+		if gameId == "" {
+			gameId, err = cli.Input("Game ID:")
+			if err != nil {
+				return "", "", err
+			}
+		}
+	}
+
+	return gameURL, gameId, nil
+}
+
+type gameDetails struct {
+	Id        string          `json:"id"`
+	Players   int             `json:"players"`
+	Protected bool            `json:"protected"`
+	Config    json.RawMessage `json:"config"`
+}
+
+// fetchGame returns the metadata of the game with the ID gameId.
+func fetchGame(api *server.API, gameId string) (gameDetails, error) {
+	resp, err := http.Get(api.BaseURL() + "/games/" + gameId)
+	if err != nil {
+		return gameDetails{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return gameDetails{}, errors.New("game not found")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return gameDetails{}, fmt.Errorf("invalid response code: expected: %d, got: %d", http.StatusOK, resp.StatusCode)
+	}
+
+	var details gameDetails
+	err = json.NewDecoder(resp.Body).Decode(&details)
+	return details, err
+}
+
 func init() {
 	rootCmd.AddCommand(gameCmd)
 }
@@ -3,10 +3,16 @@ package cmd
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/Bananenpro/cli"
+	"github.com/code-game-project/go-utils/cggenevents"
 	"github.com/code-game-project/go-utils/server"
 	"github.com/mattn/go-colorable"
 	"github.com/spf13/cobra"
@@ -14,9 +20,10 @@ import (
 
 // gameCreateCmd represents the game create command
 var gameCreateCmd = &cobra.Command{
-	Use:   "create",
-	Short: "Create a new game on the a server.",
-	Args:  cobra.MaximumNArgs(1),
+	Use:               "create",
+	Short:             "Create a new game on the a server.",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeGameURL,
 	Run: func(cmd *cobra.Command, args []string) {
 		var gameURL string
 		var err error
@@ -29,48 +36,267 @@ var gameCreateCmd = &cobra.Command{
 		api, err := server.NewAPI(gameURL)
 		abort(err)
 
-		type request struct {
-			Public    bool `json:"public"`
-			Protected bool `json:"protected"`
-			Config    any  `json:"config,omitempty"`
-		}
 		public, err := cmd.Flags().GetBool("public")
 		abort(err)
 		protected, err := cmd.Flags().GetBool("protected")
 		abort(err)
 
-		data, err := json.Marshal(request{
-			Public:    public,
-			Protected: protected,
-		})
-		abort(err)
-
-		body := bytes.NewBuffer(data)
-		resp, err := http.Post(api.BaseURL()+"/games", "application/json", body)
+		configFile, err := cmd.Flags().GetString("config")
 		abort(err)
-		defer resp.Body.Close()
-		if resp.StatusCode != http.StatusCreated {
-			abort(fmt.Errorf("invalid response code: expected: %d, got: %d", http.StatusCreated, resp.StatusCode))
+		var config any
+		if configFile != "" {
+			config, err = loadGameConfig(api, configFile)
+			abort(err)
 		}
 
-		type response struct {
-			GameId     string `json:"game_id"`
-			JoinSecret string `json:"join_secret"`
-		}
-		var r response
-		err = json.NewDecoder(resp.Body).Decode(&r)
+		gameId, joinSecret, err := createGame(api, public, protected, config)
 		abort(err)
 
 		out := colorable.NewColorableStdout()
-		fmt.Fprintf(out, "%sGame ID:%s %s\n", cli.Cyan, cli.Reset, r.GameId)
-		if r.JoinSecret != "" {
-			fmt.Fprintf(out, "%sJoin secret:%s %s\n", cli.Cyan, cli.Reset, r.JoinSecret)
+		fmt.Fprintf(out, "%sGame ID:%s %s\n", cli.Cyan, cli.Reset, gameId)
+		if joinSecret != "" {
+			fmt.Fprintf(out, "%sJoin secret:%s %s\n", cli.Cyan, cli.Reset, joinSecret)
 		}
 	},
 }
 
+// createGame creates a new game on the game server and returns its ID and join secret.
+func createGame(api *server.API, public, protected bool, config any) (gameId string, joinSecret string, err error) {
+	type request struct {
+		Public    bool `json:"public"`
+		Protected bool `json:"protected"`
+		Config    any  `json:"config,omitempty"`
+	}
+	data, err := json.Marshal(request{
+		Public:    public,
+		Protected: protected,
+		Config:    config,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	resp, err := http.Post(api.BaseURL()+"/games", "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return "", "", fmt.Errorf("invalid response code: expected: %d, got: %d", http.StatusCreated, resp.StatusCode)
+	}
+
+	type response struct {
+		GameId     string `json:"game_id"`
+		JoinSecret string `json:"join_secret"`
+	}
+	var r response
+	err = json.NewDecoder(resp.Body).Decode(&r)
+	return r.GameId, r.JoinSecret, err
+}
+
+// loadGameConfig reads a JSON game config from filename and validates it against the config schema in the CGE file of the game.
+func loadGameConfig(api *server.API, filename string) (map[string]any, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to open config file: %w", err)
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.UseNumber()
+	var config map[string]any
+	err = decoder.Decode(&config)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid config file: %w", err)
+	}
+
+	definitions, err := loadCGEDefinitions(api)
+	if err != nil {
+		cli.Warn("Failed to load the config schema of the game. Skipping validation: %s", err)
+		return config, nil
+	}
+	if definitions.Config == nil {
+		cli.Warn("The game does not define a config schema. Skipping validation.")
+		return config, nil
+	}
+
+	errs := definitions.validateObject(*definitions.Config, config, "config")
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("Invalid config:\n  %s", strings.Join(errs, "\n  "))
+	}
+	return config, nil
+}
+
+type cgeType struct {
+	Name    string   `json:"name"`
+	Generic *cgeType `json:"generic"`
+}
+
+type cgeProperty struct {
+	Name string  `json:"name"`
+	Type cgeType `json:"type"`
+}
+
+type cgeObject struct {
+	Name       string        `json:"name"`
+	Properties []cgeProperty `json:"properties"`
+}
+
+type cgeEnum struct {
+	Name   string `json:"name"`
+	Values []struct {
+		Name string `json:"name"`
+	} `json:"values"`
+}
+
+// cgeDefinitions contains the parts of the JSON output of cg-gen-events, which are needed to validate game configs.
+type cgeDefinitions struct {
+	Config *cgeObject  `json:"config"`
+	Types  []cgeObject `json:"types"`
+	Enums  []cgeEnum   `json:"enums"`
+}
+
+// minJSONCGEVersion is the first CGE version, whose cg-gen-events supports the json target.
+const minJSONCGEVersion = "0.3"
+
+// loadCGEDefinitions uses cg-gen-events to convert the CGE file of the game into JSON.
+func loadCGEDefinitions(api *server.API) (cgeDefinitions, error) {
+	cge, err := api.GetCGEFile()
+	if err != nil {
+		return cgeDefinitions{}, err
+	}
+	cgeVersion, err := cggenevents.ParseCGEVersion(cge)
+	if err != nil {
+		return cgeDefinitions{}, err
+	}
+	if !versionAtLeast(cgeVersion, minJSONCGEVersion) {
+		return cgeDefinitions{}, fmt.Errorf("the game uses CGE v%s, but converting CGE files to JSON requires at least v%s", cgeVersion, minJSONCGEVersion)
+	}
+
+	dir, err := os.MkdirTemp("", "codegame-cli-cge-*")
+	if err != nil {
+		return cgeDefinitions{}, err
+	}
+	defer os.RemoveAll(dir)
+
+	err = cggenevents.CGGenEvents(cgeVersion, dir, api.BaseURL(), "json")
+	if err != nil {
+		return cgeDefinitions{}, err
+	}
+
+	file, err := os.Open(filepath.Join(dir, "events.json"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cgeDefinitions{}, fmt.Errorf("cg-gen-events v%s did not generate JSON definitions", cgeVersion)
+		}
+		return cgeDefinitions{}, err
+	}
+	defer file.Close()
+
+	var definitions cgeDefinitions
+	err = json.NewDecoder(file).Decode(&definitions)
+	return definitions, err
+}
+
+func (d cgeDefinitions) validateObject(object cgeObject, value any, path string) []string {
+	values, ok := value.(map[string]any)
+	if !ok {
+		return []string{fmt.Sprintf("%s: expected object", path)}
+	}
+
+	properties := make(map[string]cgeType, len(object.Properties))
+	for _, p := range object.Properties {
+		properties[p.Name] = p.Type
+	}
+
+	errs := make([]string, 0)
+	for name, v := range values {
+		t, ok := properties[name]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("%s.%s: unknown property", path, name))
+			continue
+		}
+		errs = append(errs, d.validateValue(t, v, path+"."+name)...)
+	}
+	sort.Strings(errs)
+	return errs
+}
+
+func (d cgeDefinitions) validateValue(t cgeType, value any, path string) []string {
+	invalid := []string{fmt.Sprintf("%s: expected %s", path, t.Name)}
+	switch t.Name {
+	case "string":
+		if _, ok := value.(string); !ok {
+			return invalid
+		}
+	case "bool":
+		if _, ok := value.(bool); !ok {
+			return invalid
+		}
+	case "int", "int32", "int64":
+		if n, ok := value.(json.Number); !ok {
+			return invalid
+		} else if _, err := n.Int64(); err != nil {
+			return invalid
+		}
+	case "float", "float32", "float64":
+		if _, ok := value.(json.Number); !ok {
+			return invalid
+		}
+	case "list":
+		list, ok := value.([]any)
+		if !ok {
+			return invalid
+		}
+		if t.Generic == nil {
+			return nil
+		}
+		errs := make([]string, 0)
+		for i, v := range list {
+			errs = append(errs, d.validateValue(*t.Generic, v, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return errs
+	case "map":
+		m, ok := value.(map[string]any)
+		if !ok {
+			return invalid
+		}
+		if t.Generic == nil {
+			return nil
+		}
+		errs := make([]string, 0)
+		for k, v := range m {
+			errs = append(errs, d.validateValue(*t.Generic, v, fmt.Sprintf("%s[%s]", path, k))...)
+		}
+		return errs
+	default:
+		for _, e := range d.Enums {
+			if e.Name != t.Name {
+				continue
+			}
+			s, ok := value.(string)
+			if !ok {
+				return invalid
+			}
+			for _, v := range e.Values {
+				if v.Name == s {
+					return nil
+				}
+			}
+			return []string{fmt.Sprintf("%s: '%s' is not a valid value for %s", path, s, t.Name)}
+		}
+		for _, o := range d.Types {
+			if o.Name == t.Name {
+				return d.validateObject(o, value, path)
+			}
+		}
+	}
+	return nil
+}
+
 func init() {
 	gameCmd.AddCommand(gameCreateCmd)
 	gameCreateCmd.Flags().BoolP("public", "", false, "The game is displayed on a public game list.")
 	gameCreateCmd.Flags().BoolP("protected", "", false, "You can only join the game with the returned join secret.")
+	gameCreateCmd.Flags().StringP("config", "c", "", "A JSON file containing the game config.")
 }
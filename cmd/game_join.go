@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Bananenpro/cli"
+	"github.com/code-game-project/go-utils/server"
+	"github.com/code-game-project/go-utils/sessions"
+	"github.com/mattn/go-colorable"
+	"github.com/spf13/cobra"
+)
+
+// gameJoinCmd represents the game join command
+var gameJoinCmd = &cobra.Command{
+	Use:               "join",
+	Short:             "Join a game and store the new session.",
+	Args:              cobra.RangeArgs(0, 2),
+	ValidArgsFunction: completeGameId,
+	Run: func(cmd *cobra.Command, args []string) {
+		gameURL, gameId, err := selectGame(args)
+		abort(err)
+
+		username, err := cmd.Flags().GetString("username")
+		abort(err)
+		if username == "" {
+			username, err = cli.Input("Username:", cli.Regexp(usernameRegex, usernameRegexMessage))
+			abort(err)
+		} else {
+			abort(validateUsername(username))
+		}
+
+		joinSecret, err := cmd.Flags().GetString("join-secret")
+		abort(err)
+
+		api, err := server.NewAPI(gameURL)
+		abort(err)
+
+		if !cmd.Flags().Changed("join-secret") {
+			game, err := fetchGame(api, gameId)
+			if err != nil || game.Protected {
+				joinSecret, err = cli.InputOptional("Join secret (optional):")
+				abort(err)
+			}
+		}
+
+		playerId, playerSecret, err := joinGame(api, gameId, username, joinSecret)
+		abortf("Failed to join game: %s", err)
+
+		session := sessions.NewSession(gameURL, username, gameId, playerId, playerSecret)
+		err = saveSession(session)
+		abortf("Failed to save session: %s", err)
+
+		out := colorable.NewColorableStdout()
+		printInfoProperty(out, "Game ID", session.GameId, 14)
+		printInfoProperty(out, "Player ID", session.PlayerId, 14)
+		printInfoProperty(out, "Player Secret", maskSecret(session.PlayerSecret), 14)
+		cli.Success("Successfully joined the game as %s@%s!", session.Username, session.GameURL)
+	},
+}
+
+// joinGame creates a new player in the game and returns its ID and secret.
+func joinGame(api *server.API, gameId, username, joinSecret string) (playerId string, playerSecret string, err error) {
+	type request struct {
+		Username   string `json:"username"`
+		JoinSecret string `json:"join_secret,omitempty"`
+	}
+	data, err := json.Marshal(request{
+		Username:   username,
+		JoinSecret: joinSecret,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	resp, err := http.Post(api.BaseURL()+"/games/"+gameId+"/players", "application/json", bytes.NewBuffer(data))
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	type response struct {
+		Error        string `json:"error"`
+		PlayerId     string `json:"player_id"`
+		PlayerSecret string `json:"player_secret"`
+	}
+	var r response
+	err = json.NewDecoder(resp.Body).Decode(&r)
+	if resp.StatusCode != http.StatusCreated {
+		if err == nil && r.Error != "" {
+			return "", "", errors.New(r.Error)
+		}
+		return "", "", fmt.Errorf("invalid response code: expected: %d, got: %d", http.StatusCreated, resp.StatusCode)
+	}
+	return r.PlayerId, r.PlayerSecret, err
+}
+
+func init() {
+	gameCmd.AddCommand(gameJoinCmd)
+	gameJoinCmd.Flags().StringP("username", "u", "", "The username to join the game with.")
+	gameJoinCmd.Flags().StringP("join-secret", "", "", "The join secret of a protected game.")
+}
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/Bananenpro/cli"
 	"github.com/code-game-project/go-utils/server"
@@ -11,9 +13,10 @@ import (
 
 // gameListCmd represents the game list command
 var gameListCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List all public games of a game server.",
-	Args:  cobra.MaximumNArgs(1),
+	Use:               "list",
+	Short:             "List all public games of a game server.",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeGameURL,
 	Run: func(cmd *cobra.Command, args []string) {
 		var gameURL string
 		var err error
@@ -24,36 +27,129 @@ var gameListCmd = &cobra.Command{
 			abort(err)
 		}
 
-		api, err := server.NewAPI(gameURL)
-		abort(err)
-
 		protected, err := cmd.Flags().GetBool("protected")
 		abort(err)
 		unprotected, err := cmd.Flags().GetBool("unprotected")
 		abort(err)
+		minPlayers, err := cmd.Flags().GetInt("min-players")
+		abort(err)
+		maxPlayers, err := cmd.Flags().GetInt("max-players")
+		abort(err)
+		sortBy, err := cmd.Flags().GetString("sort")
+		abort(err)
+		sortBy = strings.ToLower(sortBy)
+		if sortBy != "" && sortBy != "players" && sortBy != "id" {
+			abort(fmt.Errorf("Cannot sort by '%s'. (possible values: players, id)", sortBy))
+		}
+		limit, err := cmd.Flags().GetInt("limit")
+		abort(err)
+		interactive, err := cmd.Flags().GetBool("select")
+		abort(err)
+
+		api, err := server.NewAPI(gameURL)
+		abort(err)
+
 		private, public, err := api.ListGames(unprotected, protected)
 		abort(err)
 
+		games := make([]server.GameListEntry, 0, len(public))
+		for _, g := range public {
+			if g.Players < minPlayers || (maxPlayers >= 0 && g.Players > maxPlayers) {
+				continue
+			}
+			games = append(games, g)
+		}
+		switch sortBy {
+		case "players":
+			sort.SliceStable(games, func(i, j int) bool {
+				return games[i].Players > games[j].Players
+			})
+		case "id":
+			sort.SliceStable(games, func(i, j int) bool {
+				return games[i].Id < games[j].Id
+			})
+		}
+		matching := len(games)
+		if limit > 0 && len(games) > limit {
+			games = games[:limit]
+		}
+
+		if interactive {
+			selectListedGame(gameURL, games)
+			return
+		}
+
+		printGameList(private, games)
+
+		players := 0
+		for _, g := range public {
+			players += g.Players
+		}
 		out := colorable.NewColorableStdout()
-		fmt.Fprintf(out, "%sPrivate:%s %d\n", cli.Cyan, cli.Reset, private)
-		if len(public) == 0 {
-			fmt.Fprintf(out, "%sPublic:%s none\n", cli.Cyan, cli.Reset)
+		fmt.Fprintf(out, "\n%sTotal:%s %d games (%d private, %d public, %d shown), %d players in public games\n", cli.Cyan, cli.Reset, private+len(public), private, len(public), len(games), players)
+		if matching > len(games) {
+			cli.Print("%d more matching games. Increase --limit to show them.", matching-len(games))
+		}
+	},
+}
+
+// selectListedGame lets the user select one of games and passes it to 'game join', 'game show' or 'share game'.
+func selectListedGame(gameURL string, games []server.GameListEntry) {
+	if len(games) == 0 {
+		cli.Print("No matching public games.")
+		return
+	}
+
+	options := make([]string, len(games))
+	for i, g := range games {
+		if g.Protected {
+			options[i] = fmt.Sprintf("%s (%d players, protected)", g.Id, g.Players)
 		} else {
-			cli.PrintColor(cli.Cyan, "Public:")
-			for _, g := range public {
-				if g.Protected {
-					cli.Print("- %s (%d players, protected)", g.Id, g.Players)
-				}
-				if !g.Protected {
-					cli.Print("- %s (%d players)", g.Id, g.Players)
-				}
+			options[i] = fmt.Sprintf("%s (%d players)", g.Id, g.Players)
+		}
+	}
+	index, err := cli.Select("Game:", options)
+	abort(err)
+
+	action, err := cli.SelectString("Action:", []string{"Join", "Show details", "Share"}, []string{"join", "show", "share"})
+	abort(err)
+
+	args := []string{gameURL, games[index].Id}
+	switch action {
+	case "join":
+		gameJoinCmd.Run(gameJoinCmd, args)
+	case "show":
+		gameShowCmd.Run(gameShowCmd, args)
+	case "share":
+		shareGameCmd.Run(shareGameCmd, args)
+	}
+}
+
+func printGameList(private int, public []server.GameListEntry) {
+	out := colorable.NewColorableStdout()
+	fmt.Fprintf(out, "%sPrivate:%s %d\n", cli.Cyan, cli.Reset, private)
+	if len(public) == 0 {
+		fmt.Fprintf(out, "%sPublic:%s none\n", cli.Cyan, cli.Reset)
+	} else {
+		cli.PrintColor(cli.Cyan, "Public:")
+		for _, g := range public {
+			if g.Protected {
+				cli.Print("- %s (%d players, protected)", g.Id, g.Players)
+			}
+			if !g.Protected {
+				cli.Print("- %s (%d players)", g.Id, g.Players)
 			}
 		}
-	},
+	}
 }
 
 func init() {
 	gameCmd.AddCommand(gameListCmd)
 	gameListCmd.Flags().BoolP("protected", "", false, "Only show protected games.")
 	gameListCmd.Flags().BoolP("unprotected", "", false, "Only show unprotected games.")
+	gameListCmd.Flags().IntP("min-players", "", 0, "Only show games with at least this many players.")
+	gameListCmd.Flags().IntP("max-players", "", -1, "Only show games with at most this many players.")
+	gameListCmd.Flags().StringP("sort", "", "", "Sort the games. (possible values: players, id)")
+	gameListCmd.Flags().IntP("limit", "n", 0, "Show at most this many games.")
+	gameListCmd.Flags().BoolP("select", "s", false, "Interactively select a game to join, show or share.")
 }
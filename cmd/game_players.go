@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/Bananenpro/cli"
+	"github.com/code-game-project/go-utils/server"
+	"github.com/spf13/cobra"
+)
+
+// gamePlayersCmd represents the game players command
+var gamePlayersCmd = &cobra.Command{
+	Use:               "players",
+	Short:             "List all players of a game.",
+	Args:              cobra.RangeArgs(0, 2),
+	ValidArgsFunction: completeGameId,
+	Run: func(cmd *cobra.Command, args []string) {
+		gameURL, gameId, err := selectGame(args)
+		abort(err)
+
+		api, err := server.NewAPI(gameURL)
+		abort(err)
+
+		players, err := api.GetPlayers(gameId)
+		abortf("Failed to fetch players: %s", err)
+
+		if len(players) == 0 {
+			cli.Print("No players.")
+			return
+		}
+		printPlayers(players, "")
+	},
+}
+
+func init() {
+	gameCmd.AddCommand(gamePlayersCmd)
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/Bananenpro/cli"
+	"github.com/code-game-project/go-utils/server"
+	"github.com/mattn/go-colorable"
+	"github.com/spf13/cobra"
+)
+
+// gameShowCmd represents the game show command
+var gameShowCmd = &cobra.Command{
+	Use:               "show",
+	Short:             "Show the details of a game.",
+	Args:              cobra.RangeArgs(0, 2),
+	ValidArgsFunction: completeGameId,
+	Run: func(cmd *cobra.Command, args []string) {
+		gameURL, gameId, err := selectGame(args)
+		abort(err)
+
+		api, err := server.NewAPI(gameURL)
+		abort(err)
+
+		game, err := fetchGame(api, gameId)
+		abortf("Failed to fetch game: %s", err)
+
+		out := colorable.NewColorableStdout()
+		printInfoProperty(out, "Game ID", game.Id, 10)
+		printInfoProperty(out, "Protected", fmt.Sprintf("%t", game.Protected), 10)
+		printInfoProperty(out, "Players", fmt.Sprintf("%d", game.Players), 10)
+
+		players, err := api.GetPlayers(gameId)
+		if err == nil {
+			printPlayers(players, "  ")
+		}
+
+		if len(game.Config) > 0 && string(game.Config) != "null" {
+			var config bytes.Buffer
+			err = json.Indent(&config, game.Config, "", "  ")
+			if err == nil {
+				printInfoProperty(out, "Config", config.String(), 10)
+			}
+		}
+	},
+}
+
+// printPlayers prints a sorted list of the usernames and IDs in players.
+func printPlayers(players map[string]string, indent string) {
+	ids := make([]string, 0, len(players))
+	for id := range players {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return players[ids[i]] < players[ids[j]]
+	})
+	for _, id := range ids {
+		cli.Print("%s- %s (%s)", indent, players[id], id)
+	}
+}
+
+func init() {
+	gameCmd.AddCommand(gameShowCmd)
+}
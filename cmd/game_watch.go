@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Bananenpro/cli"
+	"github.com/code-game-project/go-utils/server"
+	"github.com/spf13/cobra"
+)
+
+// gameWatchCmd represents the game watch command
+var gameWatchCmd = &cobra.Command{
+	Use:               "watch",
+	Short:             "Periodically refresh the list of public games of a game server.",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeGameURL,
+	Run: func(cmd *cobra.Command, args []string) {
+		var gameURL string
+		var err error
+		if len(args) > 0 {
+			gameURL = args[0]
+		} else if gameURL = findGameURL(); gameURL == "" {
+			gameURL, err = cli.Input("Game URL:")
+			abort(err)
+		}
+
+		interval, err := cmd.Flags().GetDuration("interval")
+		abort(err)
+		if interval < time.Second {
+			abort(errors.New("the interval must be at least 1s"))
+		}
+		protected, err := cmd.Flags().GetBool("protected")
+		abort(err)
+		unprotected, err := cmd.Flags().GetBool("unprotected")
+		abort(err)
+
+		api, err := server.NewAPI(gameURL)
+		abort(err)
+
+		for {
+			private, public, err := api.ListGames(unprotected, protected)
+			cli.Clear()
+			cli.PrintColor(cli.CyanBold, "%s (%s, every %s)", api.BaseURL(), time.Now().Format("15:04:05"), interval)
+			if err != nil {
+				cli.Error(err.Error())
+			} else {
+				printGameList(private, public)
+			}
+			time.Sleep(interval)
+		}
+	},
+}
+
+func init() {
+	gameCmd.AddCommand(gameWatchCmd)
+	gameWatchCmd.Flags().DurationP("interval", "i", 5*time.Second, "The time between two refreshes.")
+	gameWatchCmd.Flags().BoolP("protected", "", false, "Only show protected games.")
+	gameWatchCmd.Flags().BoolP("unprotected", "", false, "Only show unprotected games.")
+}
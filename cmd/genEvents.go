@@ -47,8 +47,10 @@ var genEventsCmd = &cobra.Command{
 				filename = filepath.Join(root, "events.cge")
 				languages = []string{data.Lang}
 				switch data.Lang {
-				case "go":
-					output = filepath.Join(root, strings.ReplaceAll(strings.ReplaceAll(data.Game, "_", ""), "-", ""))
+				case "go", "java", "ts":
+					dir, err := eventsOutputDir(data.Lang, data.Game, data.LangConfig)
+					abort(err)
+					output = filepath.Join(root, dir)
 				default:
 					abort(errors.New("Expected game URL."))
 				}
@@ -97,6 +99,55 @@ var genEventsCmd = &cobra.Command{
 	},
 }
 
+// generateServerEvents generates the event definitions of the server project in root from its events.cge file.
+func generateServerEvents(data *cgfile.CodeGameFileData, root string) error {
+	cgePath := filepath.Join(root, "events.cge")
+	cge, err := os.ReadFile(cgePath)
+	if err != nil {
+		return fmt.Errorf("Failed to read events.cge: %w", err)
+	}
+	cgeVersion, err := cggenevents.ParseCGEVersion(string(cge))
+	if err != nil {
+		return err
+	}
+	dir, err := eventsOutputDir(data.Lang, data.Game, data.LangConfig)
+	if err != nil || dir == "" {
+		return err
+	}
+	return cggenevents.CGGenEvents(cgeVersion, filepath.Join(root, dir), cgePath, data.Lang)
+}
+
+// eventsOutputDir returns the directory relative to the project root, into which the event definitions of game are generated for lang.
+// It returns an empty string if no event definitions are generated for lang.
+func eventsOutputDir(lang, game string, langConfig map[string]any) (string, error) {
+	switch lang {
+	case "cs":
+		return strings.ReplaceAll(strings.Title(strings.ReplaceAll(strings.ReplaceAll(game, "_", " "), "-", " ")), " ", ""), nil
+	case "go":
+		return strings.ReplaceAll(strings.ReplaceAll(game, "-", ""), "_", ""), nil
+	case "java":
+		packageConf, ok := langConfig["package"]
+		if !ok {
+			return "", errors.New("Missing language config field `package` in .codegame.json!")
+		}
+		packageName, _ := packageConf.(string)
+		if packageName == "" {
+			return "", errors.New("Empty language config field `package` in .codegame.json!")
+		}
+		gameDir := filepath.Join("src", "main", "java")
+		pkgDir := filepath.Join(strings.Split(packageName, ".")...)
+		return filepath.Join(gameDir, pkgDir, strings.ReplaceAll(strings.ReplaceAll(strings.ToLower(game), "_", ""), "-", "")), nil
+	case "py":
+		return strings.ReplaceAll(strings.ToLower(game), "-", "_"), nil
+	case "rs":
+		return filepath.Join("src", strings.ReplaceAll(strings.ToLower(game), "-", "_")), nil
+	case "ts":
+		return filepath.Join("src", game), nil
+	default:
+		return "", nil
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(genEventsCmd)
 	genEventsCmd.Flags().StringP("output", "o", ".", "The directory where every file will be generated into. (Will be created if it does not exist.)")
@@ -0,0 +1,14 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// writeJSON writes data as a JSON response with status. It is shared by the HTTP servers of the CLI ('replay serve' and 'share serve').
+// The Content-Type header is always application/json. Encoding errors are ignored, because the status has already been sent.
+func writeJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
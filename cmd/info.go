@@ -16,9 +16,10 @@ import (
 
 // infoCmd represents the info command
 var infoCmd = &cobra.Command{
-	Use:   "info",
-	Short: "Display some information about a game server.",
-	Args:  cobra.MaximumNArgs(1),
+	Use:               "info",
+	Short:             "Display some information about a game server.",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeGameURL,
 	Run: func(cmd *cobra.Command, args []string) {
 		var url string
 		if len(args) > 0 {
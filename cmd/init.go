@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Bananenpro/cli"
+	"github.com/code-game-project/go-utils/cgfile"
+	"github.com/code-game-project/go-utils/cggenevents"
+	"github.com/code-game-project/go-utils/external"
+	"github.com/code-game-project/go-utils/server"
+	"github.com/spf13/cobra"
+)
+
+// initCmd represents the init command
+var initCmd = &cobra.Command{
+	Use:   "init [url]",
+	Short: "Turn the current directory into a CodeGame client project.",
+	Long: `Turn the current directory into a CodeGame client project.
+
+The language is detected from go.mod, package.json, pom.xml, *.csproj, pyproject.toml, requirements.txt or Cargo.toml files unless --lang is specified.
+init writes .codegame.json and generates the event definitions of the game.
+Existing files are never overwritten without confirmation (or --force).
+
+Run 'codegame update' afterwards to install the client library.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeGameURL,
+	Run: func(cmd *cobra.Command, args []string) {
+		language, err := cmd.Flags().GetString("lang")
+		abort(err)
+		javaPackage, err := cmd.Flags().GetString("package")
+		abort(err)
+		force, err := cmd.Flags().GetBool("force")
+		abort(err)
+
+		if language != "" && !containsString(supportedLanguages["client"], language) {
+			abort(fmt.Errorf("unsupported language '%s' (possible values: %s)", language, strings.Join(supportedLanguages["client"], ", ")))
+		}
+		if language == "" {
+			language, err = detectLanguage(".")
+			abort(err)
+		}
+
+		var url string
+		if len(args) > 0 {
+			url = args[0]
+		} else if isInteractive() {
+			url, err = cli.Input("Game server URL:")
+			abort(err)
+		} else {
+			abort(errors.New("no game URL specified"))
+		}
+		url = external.TrimURL(url)
+
+		api, err := server.NewAPI(url)
+		abort(err)
+		info, err := api.FetchGameInfo()
+		abortf("Failed to fetch game info: %s", err)
+		cge, err := api.GetCGEFile()
+		abortf("Failed to fetch CGE file: %s", err)
+		cgeVersion, err := cggenevents.ParseCGEVersion(cge)
+		abort(err)
+
+		file := &cgfile.CodeGameFileData{
+			Game:        info.Name,
+			GameVersion: info.Version,
+			Type:        "client",
+			Lang:        language,
+			URL:         url,
+		}
+		if language == "java" {
+			if javaPackage == "" {
+				if !isInteractive() {
+					abort(errors.New("no Java package specified (use --package)"))
+				}
+				javaPackage, err = cli.Input("Java package:", cli.Regexp(javaPackageRegex, "Invalid Java package name."))
+				abort(err)
+			} else if !javaPackageRegex.MatchString(javaPackage) {
+				abort(fmt.Errorf("invalid Java package name '%s'", javaPackage))
+			}
+			file.LangConfig = map[string]any{"package": javaPackage}
+		}
+
+		eventsOutput, err := eventsOutputDir(language, info.Name, file.LangConfig)
+		abort(err)
+
+		tmp, err := os.MkdirTemp("", "codegame-cli-init-*")
+		abort(err)
+		defer os.RemoveAll(tmp)
+		if eventsOutput != "" {
+			err = cggenevents.CGGenEvents(cgeVersion, filepath.Join(tmp, eventsOutput), api.BaseURL(), language)
+			abortf("Failed to generate event definitions: %s", err)
+		}
+		err = file.Write(tmp)
+		abort(err)
+
+		conflicts, err := findConflictingFiles(tmp, ".")
+		abort(err)
+		if len(conflicts) > 0 && !force {
+			if !isInteractive() {
+				abort(fmt.Errorf("refusing to overwrite %s (use --force)", strings.Join(conflicts, ", ")))
+			}
+			cli.Warn("The following files already exist and will be overwritten:")
+			for _, c := range conflicts {
+				cli.Print("  %s", c)
+			}
+			yes, err := cli.YesNo("Overwrite them?", false)
+			abort(err)
+			if !yes {
+				abort(cli.ErrCanceled)
+			}
+		}
+
+		abortf("Failed to write files: %s", copyTree(tmp, "."))
+
+		cli.Success("Successfully initialized a %s client for %s.", languageNames[language], info.Name)
+		if !containsString(nativeLanguages, language) {
+			cli.Print("Run `codegame update` to install the client library.")
+		}
+	},
+}
+
+// languageMarkers maps files, which are typical for a language, to the language.
+var languageMarkers = []struct {
+	pattern string
+	lang    string
+}{
+	{"go.mod", "go"},
+	{"pom.xml", "java"},
+	{"*.csproj", "cs"},
+	{"package.json", "js"},
+	{"pyproject.toml", "py"},
+	{"requirements.txt", "py"},
+	{"Cargo.toml", "rs"},
+}
+
+// detectLanguage detects the language of the project in dir. It asks the user if multiple languages are detected.
+func detectLanguage(dir string) (string, error) {
+	languages := make([]string, 0, 1)
+	for _, m := range languageMarkers {
+		matches, _ := filepath.Glob(filepath.Join(dir, m.pattern))
+		if len(matches) == 0 {
+			continue
+		}
+		lang := m.lang
+		if lang == "js" {
+			if _, err := os.Stat(filepath.Join(dir, "tsconfig.json")); err == nil {
+				lang = "ts"
+			}
+		}
+		languages = append(languages, lang)
+	}
+
+	switch {
+	case len(languages) == 1:
+		cli.Print("Detected language: %s", languageNames[languages[0]])
+		return languages[0], nil
+	case len(languages) == 0:
+		languages = supportedLanguages["client"]
+	}
+	if !isInteractive() {
+		return "", errors.New("failed to detect the language (use --lang)")
+	}
+	names := make([]string, len(languages))
+	for i, l := range languages {
+		names[i] = languageNames[l]
+	}
+	return cli.SelectString("Language:", names, languages)
+}
+
+// findConflictingFiles returns the files in src, which exist in dst with a different content.
+func findConflictingFiles(src, dst string) ([]string, error) {
+	conflicts := make([]string, 0)
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		existing, err := os.ReadFile(filepath.Join(dst, rel))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(content, existing) {
+			conflicts = append(conflicts, rel)
+		}
+		return nil
+	})
+	sort.Strings(conflicts)
+	return conflicts, err
+}
+
+// copyTree copies all files in src to dst. Files and directories with a name in exclude are skipped.
+func copyTree(src, dst string, exclude ...string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if rel != "." && containsString(exclude, d.Name()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, content, 0o644)
+	})
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+	initCmd.Flags().StringP("lang", "l", "", "The programming language of the project. (possible values: cs, go, java, js, py, rs, ts)")
+	initCmd.Flags().StringP("package", "", "", "The Java package of the project.")
+	initCmd.Flags().BoolP("force", "f", false, "Overwrite existing files without confirmation.")
+	initCmd.RegisterFlagCompletionFunc("lang", completeLanguage)
+}
@@ -0,0 +1,356 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"text/template"
+
+	_ "embed"
+
+	"github.com/Bananenpro/cli"
+	"github.com/code-game-project/go-utils/cgfile"
+)
+
+// nativeLanguages are client languages without a language module. Projects in these languages are created, run, built and updated
+// by the CLI itself.
+var nativeLanguages = []string{"py", "rs"}
+
+//go:embed templates/native/py/main.py.tmpl
+var nativePythonMain string
+
+//go:embed templates/native/py/requirements.txt.tmpl
+var nativePythonRequirements string
+
+//go:embed templates/native/py/gitignore.tmpl
+var nativePythonGitignore string
+
+//go:embed templates/native/rs/Cargo.toml.tmpl
+var nativeRustCargo string
+
+//go:embed templates/native/rs/main.rs.tmpl
+var nativeRustMain string
+
+//go:embed templates/native/rs/gitignore.tmpl
+var nativeRustGitignore string
+
+var crateNameRegex = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// newNative creates the files of a new client project for file in the current directory, which must be written in one of nativeLanguages.
+// The event definitions are not generated.
+func newNative(file *cgfile.CodeGameFileData) error {
+	var templates map[string]string
+	switch file.Lang {
+	case "py":
+		templates = map[string]string{
+			"main.py":          nativePythonMain,
+			"requirements.txt": nativePythonRequirements,
+			".gitignore":       nativePythonGitignore,
+		}
+	case "rs":
+		templates = map[string]string{
+			"Cargo.toml":                    nativeRustCargo,
+			filepath.Join("src", "main.rs"): nativeRustMain,
+			".gitignore":                    nativeRustGitignore,
+		}
+	default:
+		return fmt.Errorf("'new client' is not supported for '%s'", file.Lang)
+	}
+
+	eventsOutput, err := eventsOutputDir(file.Lang, file.Game, file.LangConfig)
+	if err != nil {
+		return err
+	}
+	type data struct {
+		Game string
+		// Package is the directory of the event definitions.
+		Package string
+		// Crate is the name of the Rust package.
+		Crate string
+	}
+	d := data{
+		Game:    file.Game,
+		Package: filepath.Base(eventsOutput),
+		Crate:   strings.Trim(crateNameRegex.ReplaceAllString(strings.ToLower(file.Game), "_"), "_"),
+	}
+	if d.Crate == "" {
+		d.Crate = "client"
+	}
+
+	for name, text := range templates {
+		tmpl, err := template.New(name).Parse(text)
+		if err != nil {
+			return err
+		}
+		err = os.MkdirAll(filepath.Dir(name), 0o755)
+		if err != nil {
+			return err
+		}
+		f, err := os.Create(name)
+		if err != nil {
+			return fmt.Errorf("Failed to create %s: %w", name, err)
+		}
+		err = tmpl.Execute(f, d)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runNative runs the client project in the current directory, which must be written in one of nativeLanguages.
+func runNative(data *cgfile.CodeGameFileData, args []string) error {
+	switch data.Lang {
+	case "py":
+		python, err := preparePythonVenv()
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat("main.py"); err != nil {
+			return errors.New("main.py not found in the project root")
+		}
+		return runNativeCommand(data.URL, python, append([]string{"main.py"}, args...)...)
+	case "rs":
+		return runNativeCommand(data.URL, "cargo", append([]string{"run", "--quiet", "--"}, args...)...)
+	default:
+		return fmt.Errorf("'run' is not supported for '%s'", data.Lang)
+	}
+}
+
+// buildNative builds the client project in the current directory, which must be written in one of nativeLanguages.
+// The game URL is injected into the build, which makes the CG_GAME_URL environment variable optional.
+func buildNative(data *cgfile.CodeGameFileData, output, targetOS, targetArch string) error {
+	switch data.Lang {
+	case "py":
+		return buildPython(data, output, targetOS, targetArch)
+	case "rs":
+		return buildRust(data, output, targetOS, targetArch)
+	default:
+		return fmt.Errorf("'build' is not supported for '%s'", data.Lang)
+	}
+}
+
+// runNativeCommand executes name with args, stdio of the CLI and CG_GAME_URL set to gameURL.
+func runNativeCommand(gameURL, name string, args ...string) error {
+	command := exec.Command(name, args...)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	command.Env = append(os.Environ(), "CG_GAME_URL="+gameURL)
+	return command.Run()
+}
+
+// pythonCandidates are the names under which a Python 3 interpreter is commonly installed.
+// On Windows it is often only available as 'python' or through the 'py' launcher.
+var pythonCandidates = [][]string{{"python3"}, {"python"}, {"py", "-3"}}
+
+// findPython returns the command (including arguments) which runs a Python 3 interpreter.
+func findPython() ([]string, error) {
+	for _, candidate := range pythonCandidates {
+		if _, err := exec.LookPath(candidate[0]); err != nil {
+			continue
+		}
+		out, err := exec.Command(candidate[0], append(candidate[1:], "--version")...).CombinedOutput()
+		if err == nil && strings.HasPrefix(strings.TrimSpace(string(out)), "Python 3") {
+			return candidate, nil
+		}
+	}
+	return nil, errors.New("Python 3 is not installed (tried python3, python and py)")
+}
+
+const pythonVenvDir = ".venv"
+
+// venvPython returns the path of the Python interpreter in the virtualenv of the current project.
+func venvPython() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(pythonVenvDir, "Scripts", "python.exe")
+	}
+	return filepath.Join(pythonVenvDir, "bin", "python")
+}
+
+// preparePythonVenv creates the virtualenv of the project in the current directory if it does not exist and installs
+// the dependencies in requirements.txt or pyproject.toml if they changed since the last installation.
+// It returns the path of the Python interpreter in the virtualenv.
+func preparePythonVenv() (string, error) {
+	python := venvPython()
+	if _, err := os.Stat(python); err != nil {
+		system, err := findPython()
+		if err != nil {
+			return "", err
+		}
+		cli.Print("Creating virtualenv in '%s'...", pythonVenvDir)
+		command := exec.Command(system[0], append(system[1:], "-m", "venv", pythonVenvDir)...)
+		command.Stdout = os.Stdout
+		command.Stderr = os.Stderr
+		if err = command.Run(); err != nil {
+			return "", fmt.Errorf("failed to create virtualenv: %w", err)
+		}
+	}
+
+	var installArgs []string
+	var dependencyFile string
+	if _, err := os.Stat("requirements.txt"); err == nil {
+		dependencyFile = "requirements.txt"
+		installArgs = []string{"-r", "requirements.txt"}
+	} else if _, err := os.Stat("pyproject.toml"); err == nil {
+		dependencyFile = "pyproject.toml"
+		installArgs = []string{"."}
+	} else {
+		return python, nil
+	}
+
+	marker := filepath.Join(pythonVenvDir, ".codegame-installed")
+	if markerInfo, err := os.Stat(marker); err == nil {
+		if depInfo, err := os.Stat(dependencyFile); err == nil && !depInfo.ModTime().After(markerInfo.ModTime()) {
+			return python, nil
+		}
+	}
+
+	cli.Print("Installing dependencies from %s...", dependencyFile)
+	command := exec.Command(python, append([]string{"-m", "pip", "install", "--quiet", "--disable-pip-version-check"}, installArgs...)...)
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	if err := command.Run(); err != nil {
+		return "", fmt.Errorf("failed to install dependencies: %w", err)
+	}
+	return python, os.WriteFile(marker, nil, 0o644)
+}
+
+// buildPython packages the project with its dependencies into an executable zip archive (.pyz), which can be run with any Python 3 interpreter.
+// The dependencies are installed with the pip of the project virtualenv.
+func buildPython(data *cgfile.CodeGameFileData, output, targetOS, targetArch string) error {
+	if targetOS != "current" || targetArch != "current" {
+		cli.Warn("Python builds are platform independent. Ignoring --os and --arch.")
+	}
+	if _, err := os.Stat("main.py"); err != nil {
+		return errors.New("main.py not found in the project root")
+	}
+	python, err := preparePythonVenv()
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		output = strings.ReplaceAll(data.Game, "-", "_") + ".pyz"
+	}
+
+	dir, err := os.MkdirTemp("", "codegame-build-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	err = copyTree(".", dir, pythonVenvDir, ".git", "__pycache__", output)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat("requirements.txt"); err == nil {
+		command := exec.Command(python, "-m", "pip", "install", "--quiet", "--disable-pip-version-check", "--target", dir, "-r", "requirements.txt")
+		command.Stdout = os.Stdout
+		command.Stderr = os.Stderr
+		if err = command.Run(); err != nil {
+			return fmt.Errorf("failed to install dependencies: %w", err)
+		}
+	}
+
+	launcher := fmt.Sprintf("import os, runpy\nos.environ.setdefault(\"CG_GAME_URL\", %q)\nrunpy.run_module(\"main\", run_name=\"__main__\")\n", data.URL)
+	err = os.WriteFile(filepath.Join(dir, "__main__.py"), []byte(launcher), 0o644)
+	if err != nil {
+		return err
+	}
+
+	command := exec.Command(python, "-m", "zipapp", dir, "--output", output, "--python", "/usr/bin/env python3")
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	if err = command.Run(); err != nil {
+		return fmt.Errorf("failed to create %s: %w", output, err)
+	}
+	cli.Success("Successfully built '%s'.", output)
+	return nil
+}
+
+// rustTargets maps the --os and --arch values of 'build' to Rust target triples.
+var rustTargets = map[string]map[string]string{
+	"linux": {
+		"x64":   "x86_64-unknown-linux-gnu",
+		"x86":   "i686-unknown-linux-gnu",
+		"arm32": "armv7-unknown-linux-gnueabihf",
+		"arm64": "aarch64-unknown-linux-gnu",
+	},
+	"windows": {
+		"x64":   "x86_64-pc-windows-gnu",
+		"x86":   "i686-pc-windows-gnu",
+		"arm64": "aarch64-pc-windows-msvc",
+	},
+	"macos": {
+		"x64":   "x86_64-apple-darwin",
+		"arm64": "aarch64-apple-darwin",
+	},
+}
+
+var cargoPackageNameRegex = regexp.MustCompile(`(?m)^\[package\][^\[]*?^name\s*=\s*"([^"]+)"`)
+
+// buildRust builds the project with 'cargo build --release' for the target specified by targetOS and targetArch and copies the binary to output.
+// The game URL is available to the program at compile time with option_env!("CG_GAME_URL").
+func buildRust(data *cgfile.CodeGameFileData, output, targetOS, targetArch string) error {
+	manifest, err := os.ReadFile("Cargo.toml")
+	if err != nil {
+		return fmt.Errorf("failed to read Cargo.toml: %w", err)
+	}
+	match := cargoPackageNameRegex.FindSubmatch(manifest)
+	if match == nil {
+		return errors.New("missing package name in Cargo.toml")
+	}
+	name := string(match[1])
+
+	var target string
+	if targetOS != "current" || targetArch != "current" {
+		if targetOS == "current" {
+			targetOS = map[string]string{"darwin": "macos"}[runtime.GOOS]
+			if targetOS == "" {
+				targetOS = runtime.GOOS
+			}
+		}
+		if targetArch == "current" {
+			targetArch = map[string]string{"amd64": "x64", "386": "x86", "arm": "arm32", "arm64": "arm64"}[runtime.GOARCH]
+		}
+		target = rustTargets[targetOS][targetArch]
+		if target == "" {
+			return fmt.Errorf("unsupported target: %s %s", targetOS, targetArch)
+		}
+	}
+
+	args := []string{"build", "--release"}
+	binary := filepath.Join("target", "release", name)
+	if target != "" {
+		args = append(args, "--target", target)
+		binary = filepath.Join("target", target, "release", name)
+	}
+	if targetOS == "windows" || (targetOS == "current" && runtime.GOOS == "windows") {
+		binary += ".exe"
+	}
+	if output == "" {
+		output = filepath.Base(binary)
+	}
+
+	err = runNativeCommand(data.URL, "cargo", args...)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(binary)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(output, content, 0o755)
+	if err != nil {
+		return err
+	}
+	cli.Success("Successfully built '%s'.", output)
+	return nil
+}
@@ -29,19 +29,40 @@ var projectNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_\-]*$`)
 
 // newCmd represents the new command
 var newCmd = &cobra.Command{
-	Use:   "new",
-	Short: "Create a new CodeGame application.",
-	Args:  cobra.RangeArgs(0, 1),
+	Use:       "new",
+	Short:     "Create a new CodeGame application.",
+	Args:      cobra.RangeArgs(0, 1),
+	ValidArgs: []string{"client", "server", "workspace"},
 	Run: func(cmd *cobra.Command, args []string) {
+		language, err := cmd.Flags().GetString("lang")
+		abort(err)
+		clientLanguages, err := cmd.Flags().GetStringSlice("clients")
+		abort(err)
+
 		var project string
 		if len(args) > 0 {
 			project = strings.ToLower(args[0])
 		} else {
-			var err error
-			project, err = cli.SelectString("Project type:", []string{"Game Client", "Game Server"}, []string{"client", "server"})
+			project, err = cli.SelectString("Project type:", []string{"Game Client", "Game Server", "Workspace (Game Server and Clients)"}, []string{"client", "server", "workspace"})
 			abort(err)
 		}
 
+		serverOrClient := project
+		if project == "workspace" {
+			serverOrClient = "server"
+		}
+		if languages, ok := supportedLanguages[serverOrClient]; ok && language != "" && !containsString(languages, language) {
+			abort(fmt.Errorf("unsupported language '%s' for %s projects (possible values: %s)", language, serverOrClient, strings.Join(languages, ", ")))
+		}
+		if project == "workspace" && cmd.Flags().Changed("clients") && len(clientLanguages) == 0 {
+			abort(errors.New("a workspace requires at least one client language"))
+		}
+		for _, l := range clientLanguages {
+			if !containsString(supportedLanguages["client"], l) {
+				abort(fmt.Errorf("unsupported client language '%s' (possible values: %s)", l, strings.Join(supportedLanguages["client"], ", ")))
+			}
+		}
+
 		projectName, err := cli.Input("Project name:", cli.Regexp(projectNameRegexp, "Project name must only contain 'a'-'z','A'-'Z','0'-'9','-','_'."))
 		abort(err)
 
@@ -56,9 +77,11 @@ var newCmd = &cobra.Command{
 
 		switch project {
 		case "server":
-			err = newServer(projectName)
+			err = newServer(projectName, language)
 		case "client":
-			err = newClient()
+			err = newClient(language)
+		case "workspace":
+			err = newWorkspace(projectName, language, clientLanguages)
 		default:
 			err = fmt.Errorf("unknown project type: %s", project)
 		}
@@ -81,12 +104,19 @@ var newCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(newCmd)
+	newCmd.Flags().StringP("lang", "l", "", "The programming language of the project or the server of a workspace. (possible values: "+strings.Join(supportedLanguages["client"], ", ")+" for clients, "+strings.Join(supportedLanguages["server"], ", ")+" for servers)")
+	newCmd.Flags().StringSliceP("clients", "", nil, "The languages of the clients of a workspace. (possible values: "+strings.Join(supportedLanguages["client"], ", ")+")")
+	newCmd.RegisterFlagCompletionFunc("lang", completeLanguage)
+	newCmd.RegisterFlagCompletionFunc("clients", completeValues(supportedLanguages["client"]...))
 }
 
-func newServer(projectName string) error {
-	language, err := cli.SelectString("Language:", []string{"Go"}, []string{"go"})
-	if err != nil {
-		return err
+func newServer(projectName, language string) error {
+	var err error
+	if language == "" {
+		language, err = cli.SelectString("Language:", []string{"Go", "Java", "TypeScript"}, []string{"go", "java", "ts"})
+		if err != nil {
+			return err
+		}
 	}
 
 	file := cgfile.CodeGameFileData{
@@ -94,6 +124,13 @@ func newServer(projectName string) error {
 		Type: "server",
 		Lang: language,
 	}
+	if language == "java" {
+		javaPackage, err := cli.Input("Java package:", cli.Regexp(javaPackageRegex, "Invalid Java package name."))
+		if err != nil {
+			return err
+		}
+		file.LangConfig = map[string]any{"package": javaPackage}
+	}
 	err = file.Write("")
 	if err != nil {
 		return fmt.Errorf("Failed to create .codegame.json: %w", err)
@@ -105,7 +142,7 @@ func newServer(projectName string) error {
 	}
 
 	switch language {
-	case "go":
+	case "go", "java", "ts":
 		err = modules.ExecuteNewServer(newData)
 	default:
 		err = fmt.Errorf("'new server' is not supported for '%s'", language)
@@ -135,13 +172,22 @@ func newServer(projectName string) error {
 	}
 	defer eventsFile.Close()
 
-	return tmpl.Execute(eventsFile, data{
+	err = tmpl.Execute(eventsFile, data{
 		SnakeCaseName: strings.ReplaceAll(projectName, "-", "_"),
 		CGEVersion:    cgeVersion,
 	})
+	if err != nil {
+		return err
+	}
+
+	switch language {
+	case "java", "ts":
+		return generateServerEvents(&file, "")
+	}
+	return nil
 }
 
-func newClient() error {
+func newClient(language string) error {
 	url, err := cli.Input("Game server URL:")
 	if err != nil {
 		return err
@@ -164,44 +210,61 @@ func newClient() error {
 		return err
 	}
 
-	language, err := cli.SelectString("Language:", []string{"C#", "Go", "Java", "JavaScript", "TypeScript"}, []string{"cs", "go", "java", "js", "ts"})
-	if err != nil {
-		return err
+	if language == "" {
+		language, err = cli.SelectString("Language:", []string{"C#", "Go", "Java", "JavaScript", "Python", "Rust", "TypeScript"}, []string{"cs", "go", "java", "js", "py", "rs", "ts"})
+		if err != nil {
+			return err
+		}
 	}
 
+	return createClient(language, info.Name, info.Version, url, info.CGVersion, cgeVersion, external.BaseURL("http", external.IsTLS(url), url))
+}
+
+// createClient creates a client project for the game in the current directory.
+// cgePath is the URL or file path of the CGE file used to generate the event definitions.
+// If cgVersion is empty, the latest client library is used.
+func createClient(language, game, gameVersion, url, cgVersion, cgeVersion, cgePath string) error {
 	file := &cgfile.CodeGameFileData{
-		Game:        info.Name,
-		GameVersion: info.Version,
+		Game:        game,
+		GameVersion: gameVersion,
 		Type:        "client",
 		Lang:        language,
 		URL:         url,
 	}
-	err = file.Write("")
+	err := file.Write("")
 	if err != nil {
 		return fmt.Errorf("Failed to create .codegame.json: %s", err)
 	}
 
 	newData := modules.NewClientData{
-		Lang: language,
-		Name: info.Name,
-		URL:  url,
+		Lang:           language,
+		Name:           game,
+		URL:            url,
+		LibraryVersion: "latest",
 	}
 
+	var libraryRepo string
 	switch language {
 	case "cs":
-		newData.LibraryVersion = external.LibraryVersionFromCGVersion("code-game-project", "csharp-client", info.CGVersion)
-		err = modules.ExecuteNewClient(newData)
+		libraryRepo = "csharp-client"
 	case "go":
-		newData.LibraryVersion = external.LibraryVersionFromCGVersion("code-game-project", "go-client", info.CGVersion)
-		err = modules.ExecuteNewClient(newData)
+		libraryRepo = "go-client"
 	case "java":
-		newData.LibraryVersion = external.LibraryVersionFromCGVersion("code-game-project", "java-client", info.CGVersion)
-		err = modules.ExecuteNewClient(newData)
+		libraryRepo = "java-client"
 	case "js", "ts":
-		newData.LibraryVersion = external.LibraryVersionFromCGVersion("code-game-project", "javascript-client", info.CGVersion)
-		err = modules.ExecuteNewClient(newData)
+		libraryRepo = "javascript-client"
+	case "py", "rs":
+		// There is no language module. The CLI creates the project itself.
 	default:
-		err = fmt.Errorf("'new client' is not supported for '%s'", language)
+		return fmt.Errorf("'new client' is not supported for '%s'", language)
+	}
+	if libraryRepo == "" {
+		err = newNative(file)
+	} else {
+		if cgVersion != "" {
+			newData.LibraryVersion = external.LibraryVersionFromCGVersion("code-game-project", libraryRepo, cgVersion)
+		}
+		err = modules.ExecuteNewClient(newData)
 	}
 	if err != nil {
 		return err
@@ -212,29 +275,12 @@ func newClient() error {
 		return fmt.Errorf("Failed to open .codegame.json: %w", err)
 	}
 
-	if language == "cs" || language == "go" || language == "java" || language == "ts" {
-		eventsOutput := info.Name
-		switch language {
-		case "cs":
-			eventsOutput = strings.ReplaceAll(strings.Title(strings.ReplaceAll(strings.ReplaceAll(eventsOutput, "_", " "), "-", " ")), " ", "")
-		case "go":
-			eventsOutput = strings.ReplaceAll(strings.ReplaceAll(eventsOutput, "-", ""), "_", "")
-		case "java":
-			packageConf, ok := file.LangConfig["package"]
-			if !ok {
-				return errors.New("Missing language config field `package` in .codegame.json!")
-			}
-			packageName := packageConf.(string)
-			if packageConf == "" {
-				return errors.New("Empty language config field `package` in .codegame.json!")
-			}
-			gameDir := filepath.Join("src", "main", "java")
-			pkgDir := filepath.Join(strings.Split(packageName, ".")...)
-			eventsOutput = filepath.Join(gameDir, pkgDir, strings.ReplaceAll(strings.ReplaceAll(strings.ToLower(eventsOutput), "_", ""), "-", ""))
-		case "ts":
-			eventsOutput = filepath.Join("src", eventsOutput)
-		}
-		err = cggenevents.CGGenEvents(cgeVersion, eventsOutput, external.BaseURL("http", external.IsTLS(url), url), language)
+	eventsOutput, err := eventsOutputDir(language, game, file.LangConfig)
+	if err != nil {
+		return err
+	}
+	if eventsOutput != "" {
+		err = cggenevents.CGGenEvents(cgeVersion, eventsOutput, cgePath, language)
 		if err != nil {
 			return err
 		}
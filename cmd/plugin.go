@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/code-game-project/go-utils/cgfile"
+	"github.com/code-game-project/go-utils/config"
+	"github.com/spf13/cobra"
+)
+
+// pluginCmd represents the plugin command
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Manage plugins.",
+	Long: `Manage plugins.
+
+Plugins are executables named 'codegame-<name>' in the plugin directory or on the PATH,
+which can be executed with 'codegame <name>'. Plugins in the plugin directory take precedence.
+Plugins with the name of a built-in command are ignored.
+
+The following environment variables are passed to plugins in addition to the environment of the CLI:
+  CG_CLI            path of the codegame executable
+  CG_CLI_VERSION    version of the CLI
+  CG_CONFIG         CodeGame config as JSON
+  CG_PROJECT_ROOT   root directory of the current project
+  CG_PROJECT        contents of the .codegame.json file of the current project
+  CG_GAME_URL       game URL of the current project or of the active session
+  CG_USERNAME, CG_GAME_ID, CG_PLAYER_ID, CG_PLAYER_SECRET
+                    the active session of the current project`,
+}
+
+const pluginPrefix = "codegame-"
+
+type plugin struct {
+	name string
+	path string
+	// installed is true if the plugin is in the plugin directory instead of on the PATH.
+	installed bool
+}
+
+// findPlugins returns all plugins sorted by name. Plugins in the plugin directory shadow plugins on the PATH
+// and plugins earlier on the PATH shadow later ones.
+func findPlugins() []plugin {
+	plugins := make([]plugin, 0)
+	seen := make(map[string]bool)
+	dirs := append([]string{pluginsDir}, filepath.SplitList(os.Getenv("PATH"))...)
+	for i, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok || seen[name] {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, plugin{
+				name:      name,
+				path:      path,
+				installed: i == 0,
+			})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool {
+		return plugins[i].name < plugins[j].name
+	})
+	return plugins
+}
+
+// pluginName returns the name of the plugin with the file name filename.
+func pluginName(filename string) (string, bool) {
+	if !strings.HasPrefix(filename, pluginPrefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(filename, pluginPrefix)
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(name))
+		if ext != ".exe" && ext != ".bat" && ext != ".cmd" {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	} else if strings.Contains(name, ".") {
+		return "", false
+	}
+	return name, name != ""
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode()&0o111 != 0
+}
+
+// isBuiltinCommand reports whether name is the name or an alias of a built-in command.
+func isBuiltinCommand(name string) bool {
+	for _, c := range rootCmd.Commands() {
+		if _, ok := c.Annotations["plugin"]; !ok && (c.Name() == name || c.HasAlias(name)) {
+			return true
+		}
+	}
+	return name == "help" || name == "completion"
+}
+
+// registerPlugins adds a command for every plugin, which does not conflict with a built-in command.
+func registerPlugins() {
+	for _, p := range findPlugins() {
+		if isBuiltinCommand(p.name) {
+			continue
+		}
+		p := p
+		rootCmd.AddCommand(&cobra.Command{
+			Use:                p.name,
+			Short:              "Plugin: " + p.path,
+			DisableFlagParsing: true,
+			Annotations:        map[string]string{"plugin": p.path},
+			Run: func(_ *cobra.Command, args []string) {
+				runPlugin(p, args)
+			},
+		})
+	}
+}
+
+// runPlugin executes p with args and exits with its exit code.
+func runPlugin(p plugin, args []string) {
+	command := exec.Command(p.path, args...)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	command.Env = pluginEnv()
+	err := command.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.ExitCode())
+	}
+	abortf("Failed to execute plugin: %s", err)
+}
+
+// pluginEnv returns the environment of plugins. Variables which are already set are not overwritten.
+func pluginEnv() []string {
+	vars := map[string]string{
+		"CG_CLI_VERSION": rootCmd.Version,
+	}
+	if executable, err := os.Executable(); err == nil {
+		vars["CG_CLI"] = executable
+	}
+	if data, err := json.Marshal(config.Load()); err == nil {
+		vars["CG_CONFIG"] = string(data)
+	}
+	if root, err := cgfile.FindProjectRoot(); err == nil {
+		vars["CG_PROJECT_ROOT"] = root
+		if data, err := os.ReadFile(filepath.Join(root, ".codegame.json")); err == nil {
+			vars["CG_PROJECT"] = string(data)
+		}
+		if gameURL := findGameURL(); gameURL != "" {
+			vars["CG_GAME_URL"] = gameURL
+		}
+	}
+	if session, ok := findActiveSession(); ok {
+		if _, ok := vars["CG_GAME_URL"]; !ok {
+			vars["CG_GAME_URL"] = session.GameURL
+		}
+		vars["CG_USERNAME"] = session.Username
+		vars["CG_GAME_ID"] = session.GameId
+		vars["CG_PLAYER_ID"] = session.PlayerId
+		vars["CG_PLAYER_SECRET"] = session.PlayerSecret
+	}
+
+	env := os.Environ()
+	for name, value := range vars {
+		if _, ok := os.LookupEnv(name); !ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	return env
+}
+
+func init() {
+	rootCmd.AddCommand(pluginCmd)
+}
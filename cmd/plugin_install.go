@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/Bananenpro/cli"
+	"github.com/spf13/cobra"
+)
+
+// pluginInstallCmd represents the plugin install command
+var pluginInstallCmd = &cobra.Command{
+	Use:   "install <file|url>",
+	Short: "Install a plugin from a file or URL.",
+	Long: `Install a plugin from a file or URL into the plugin directory.
+
+The name of the plugin is derived from the file name ('codegame-<name>') unless --name is specified.
+Doctor rules of the plugin can be installed with --rules (see 'codegame doctor --help').
+
+Plugins can only be downloaded over HTTPS unless --insecure is specified. With --sha256 the plugin
+is only installed if its SHA-256 checksum matches.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, err := cmd.Flags().GetString("name")
+		abort(err)
+		rulesFile, err := cmd.Flags().GetString("rules")
+		abort(err)
+		force, err := cmd.Flags().GetBool("force")
+		abort(err)
+		insecure, err := cmd.Flags().GetBool("insecure")
+		abort(err)
+		checksum, err := cmd.Flags().GetString("sha256")
+		abort(err)
+
+		source := args[0]
+		isURL := strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+		if strings.HasPrefix(source, "http://") && !insecure {
+			abort(errors.New("refusing to download a plugin over HTTP (use HTTPS or --insecure)"))
+		}
+		checksum = strings.ToLower(checksum)
+		if checksum != "" && !sha256Regex.MatchString(checksum) {
+			abort(fmt.Errorf("invalid SHA-256 checksum '%s'", checksum))
+		}
+
+		filename := filepath.Base(source)
+		if isURL {
+			filename = path.Base(strings.SplitN(source, "?", 2)[0])
+		}
+		if name == "" {
+			name = strings.TrimPrefix(strings.TrimSuffix(filename, filepath.Ext(filename)), pluginPrefix)
+		}
+		if !pluginNameRegex.MatchString(name) {
+			abort(fmt.Errorf("invalid plugin name '%s' (use --name to specify a name)", name))
+		}
+		if isBuiltinCommand(name) {
+			abort(fmt.Errorf("'%s' is the name of a built-in command", name))
+		}
+
+		var rules []doctorRuleConfig
+		if rulesFile != "" {
+			rules, err = loadDoctorRulesFile(rulesFile)
+			abortf("Failed to load doctor rules: %s", err)
+			for _, r := range rules {
+				_, err = newDoctorRuleCommand(r)
+				abortf("Invalid doctor rules: %s", err)
+			}
+		}
+
+		target := filepath.Join(pluginsDir, pluginPrefix+name)
+		if runtime.GOOS == "windows" {
+			ext := filepath.Ext(filename)
+			if ext == "" {
+				ext = ".exe"
+			}
+			target += ext
+		}
+		if _, err := os.Stat(target); err == nil && !force {
+			if !isInteractive() {
+				abort(fmt.Errorf("plugin '%s' is already installed (use --force to overwrite it)", name))
+			}
+			yes, err := cli.YesNo(fmt.Sprintf("Plugin '%s' is already installed. Overwrite it?", name), false)
+			abort(err)
+			if !yes {
+				abort(cli.ErrCanceled)
+			}
+		}
+
+		var content []byte
+		if isURL {
+			content, err = downloadPlugin(source)
+		} else {
+			content, err = os.ReadFile(source)
+		}
+		abortf("Failed to read plugin: %s", err)
+		if checksum != "" {
+			if sum := sha256.Sum256(content); hex.EncodeToString(sum[:]) != checksum {
+				abort(fmt.Errorf("SHA-256 checksum mismatch: expected %s, got %x", checksum, sum))
+			}
+		}
+
+		abortf("Failed to create plugin directory: %s", os.MkdirAll(pluginsDir, 0o755))
+		// Write to a temporary file first to not leave a broken plugin behind.
+		tmp := target + ".tmp"
+		err = os.WriteFile(tmp, content, 0o755)
+		if err == nil {
+			err = os.Rename(tmp, target)
+		}
+		if err != nil {
+			os.Remove(tmp)
+			abortf("Failed to install plugin: %s", err)
+		}
+
+		if rulesFile != "" {
+			data, err := os.ReadFile(rulesFile)
+			if err == nil {
+				err = os.WriteFile(filepath.Join(pluginsDir, name+".doctor.json"), data, 0o644)
+			}
+			abortf("Failed to install doctor rules: %s", err)
+		}
+
+		cli.Success("Successfully installed plugin '%s'. Run it with `codegame %s`.", name, name)
+		if len(rules) > 0 {
+			cli.Print("The doctor rules of the plugin are checked by `codegame doctor`.")
+		}
+	},
+}
+
+var pluginNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+var sha256Regex = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+func downloadPlugin(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func init() {
+	pluginCmd.AddCommand(pluginInstallCmd)
+	pluginInstallCmd.Flags().StringP("name", "n", "", "The name of the plugin.")
+	pluginInstallCmd.Flags().StringP("rules", "", "", "A file with doctor rules of the plugin.")
+	pluginInstallCmd.Flags().BoolP("force", "f", false, "Overwrite an installed plugin with the same name.")
+	pluginInstallCmd.Flags().BoolP("insecure", "", false, "Allow downloading the plugin over HTTP.")
+	pluginInstallCmd.Flags().StringP("sha256", "", "", "The expected SHA-256 checksum of the plugin.")
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/Bananenpro/cli"
+	"github.com/spf13/cobra"
+)
+
+// pluginListCmd represents the plugin list command
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all available plugins.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		plugins := findPlugins()
+		for _, p := range plugins {
+			cli.PrintColor(cli.CyanBold, p.name)
+			source := "PATH"
+			if p.installed {
+				source = "installed"
+			}
+			if isBuiltinCommand(p.name) {
+				cli.Print("  %s (%s, ignored because it conflicts with a built-in command)", p.path, source)
+			} else {
+				cli.Print("  %s (%s)", p.path, source)
+			}
+		}
+		if len(plugins) == 0 {
+			cli.Print("No plugins found.")
+		}
+	},
+}
+
+func init() {
+	pluginCmd.AddCommand(pluginListCmd)
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Bananenpro/cli"
+	"github.com/spf13/cobra"
+)
+
+// pluginRemoveCmd represents the plugin remove command
+var pluginRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove an installed plugin.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		var found *plugin
+		for _, p := range findPlugins() {
+			if p.name == name {
+				p := p
+				found = &p
+				break
+			}
+		}
+		if found == nil {
+			abort(fmt.Errorf("plugin '%s' is not installed", name))
+		}
+		if !found.installed {
+			abort(fmt.Errorf("plugin '%s' is not installed in %s but found on the PATH at %s, please remove it manually", name, pluginsDir, found.path))
+		}
+
+		abortf("Failed to remove plugin: %s", os.Remove(found.path))
+		err := os.Remove(filepath.Join(pluginsDir, name+".doctor.json"))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			cli.Warn("Failed to remove the doctor rules of the plugin: %s", err)
+		}
+		cli.Success("Successfully removed plugin '%s'.", name)
+	},
+}
+
+func init() {
+	pluginCmd.AddCommand(pluginRemoveCmd)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/code-game-project/go-utils/server"
+	"github.com/spf13/cobra"
+)
+
+// replayCmd represents the replay command
+var replayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Record and replay games for deterministic testing.",
+}
+
+// wsMessage is an event or command sent over a CodeGame websocket connection.
+type wsMessage struct {
+	Name string          `json:"name"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// recordedEvent is a single line in a recording file.
+type recordedEvent struct {
+	// Time contains the number of milliseconds since the start of the recording.
+	Time int64           `json:"time"`
+	Name string          `json:"name"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// loadRecording reads a recording file consisting of one JSON encoded recordedEvent per line.
+func loadRecording(filename string) ([]recordedEvent, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	events := make([]recordedEvent, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var event recordedEvent
+		err = json.Unmarshal(scanner.Bytes(), &event)
+		if err != nil {
+			return nil, fmt.Errorf("invalid recording: line %d: %w", line, err)
+		}
+		if event.Name == "" {
+			return nil, fmt.Errorf("invalid recording: line %d: missing event name", line)
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// websocketURL converts the base URL of api into a websocket URL and appends path.
+func websocketURL(api *server.API, path string) string {
+	return "ws" + strings.TrimPrefix(api.BaseURL(), "http") + path
+}
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+}
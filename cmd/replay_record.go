@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/Bananenpro/cli"
+	"github.com/code-game-project/go-utils/server"
+	"github.com/gorilla/websocket"
+	"github.com/spf13/cobra"
+)
+
+// replayRecordCmd represents the replay record command
+var replayRecordCmd = &cobra.Command{
+	Use:               "record",
+	Short:             "Record the events of a running game as a spectator.",
+	Args:              cobra.RangeArgs(0, 2),
+	ValidArgsFunction: completeGameId,
+	Run: func(cmd *cobra.Command, args []string) {
+		var gameURL string
+		var gameId string
+		var err error
+		if len(args) > 0 {
+			gameURL = args[0]
+			if len(args) > 1 {
+				gameId = args[1]
+			}
+		} else if gameURL = findGameURL(); gameURL == "" {
+			gameURL, err = cli.Input("Game URL:")
+			abort(err)
+		}
+		if gameId == "" {
+			gameId, err = cli.Input("Game ID:")
+			abort(err)
+		}
+
+		output, err := cmd.Flags().GetString("output")
+		abort(err)
+		output, err = filepath.Abs(output)
+		abort(err)
+
+		api, err := server.NewAPI(gameURL)
+		abort(err)
+
+		conn, _, err := websocket.DefaultDialer.Dial(websocketURL(api, "/games/"+gameId+"/spectate"), nil)
+		abortf("Failed to spectate game: %s", err)
+		defer conn.Close()
+
+		file, err := os.Create(output)
+		abortf("Failed to create recording file: %s", err)
+		defer file.Close()
+
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		go func() {
+			<-interrupt
+			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			conn.Close()
+		}()
+
+		cli.Print("Recording %s@%s into '%s'. Press Ctrl+C to stop.", gameId, api.BaseURL(), output)
+
+		encoder := json.NewEncoder(file)
+		start := time.Now()
+		count := 0
+		for {
+			var msg wsMessage
+			err = conn.ReadJSON(&msg)
+			if err != nil {
+				break
+			}
+			err = encoder.Encode(recordedEvent{
+				Time: time.Since(start).Milliseconds(),
+				Name: msg.Name,
+				Data: msg.Data,
+			})
+			abortf("Failed to write event: %s", err)
+			count++
+		}
+
+		cli.Success("Recorded %d events.", count)
+	},
+}
+
+func init() {
+	replayCmd.AddCommand(replayRecordCmd)
+	replayRecordCmd.Flags().StringP("output", "o", "recording.jsonl", "The file to write the recorded events to.")
+}
@@ -0,0 +1,479 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Bananenpro/cli"
+	"github.com/code-game-project/go-utils/cgfile"
+	"github.com/code-game-project/go-utils/config"
+	"github.com/code-game-project/go-utils/modules"
+	"github.com/code-game-project/go-utils/server"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/spf13/cobra"
+)
+
+// replayServeCmd represents the replay serve command
+var replayServeCmd = &cobra.Command{
+	Use:   "serve <recording> [-- <client args>...]",
+	Short: "Replay a recording to the client of the current project and capture its commands.",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		speed, err := cmd.Flags().GetFloat64("speed")
+		abort(err)
+		if speed < 0 {
+			abort(errors.New("speed must not be negative"))
+		}
+		linger, err := cmd.Flags().GetDuration("linger")
+		abort(err)
+		output, err := cmd.Flags().GetString("output")
+		abort(err)
+		if output != "" {
+			// Resolve the output file relative to the working directory before changing it to the project root.
+			output, err = filepath.Abs(output)
+			abort(err)
+		}
+		expect, err := cmd.Flags().GetString("expect")
+		abort(err)
+
+		events, err := loadRecording(args[0])
+		abortf("Failed to load recording: %s", err)
+
+		var expected []wsMessage
+		if expect != "" {
+			expected, err = loadCommands(expect)
+			abortf("Failed to load expected commands: %s", err)
+		}
+
+		root, err := cgfile.FindProjectRoot()
+		abort(err)
+		err = os.Chdir(root)
+		abort(err)
+
+		data, err := cgfile.LoadCodeGameFile("")
+		abortf("failed to load .codegame.json: %s", err)
+		if data.Type != "client" {
+			abort(errors.New("project is not a client"))
+		}
+		switch data.Lang {
+		case "cs", "go", "java", "js", "py", "rs", "ts":
+		default:
+			abort(fmt.Errorf("'replay serve' is not supported for '%s'", data.Lang))
+		}
+
+		api, err := server.NewAPI(data.URL)
+		abort(err)
+		info, err := api.FetchGameInfo()
+		abortf("Failed to fetch game info: %s", err)
+		cge, err := api.GetCGEFile()
+		abortf("Failed to fetch CGE file: %s", err)
+
+		port := os.Getenv("CG_PORT")
+		if port == "" {
+			port = fmt.Sprintf("%d", findAvailablePort(config.Load().DevPort))
+			os.Setenv("CG_PORT", port)
+		}
+
+		replay := &replayServer{
+			info:   info,
+			cge:    cge,
+			events: events,
+			speed:  speed,
+			linger: linger,
+			gameId: uuid.NewString(),
+		}
+
+		listener, err := net.Listen("tcp", "localhost:"+port)
+		abortf("Failed to start replay server: %s", err)
+		httpServer := &http.Server{Handler: replay.handler()}
+		go httpServer.Serve(listener)
+
+		cli.Print("Replaying %d events from '%s' on localhost:%s...", len(events), args[0], port)
+
+		clientArgs := args[1:]
+		runErr := replay.runClient(root, data, "localhost:"+port, clientArgs)
+		httpServer.Close()
+
+		commands := replay.capturedCommands()
+		cli.Print("Replayed %d/%d events, captured %d commands.", replay.sentEvents(), len(events), len(commands))
+
+		if output != "" {
+			err = writeCommands(output, commands)
+			abortf("Failed to write captured commands: %s", err)
+		}
+
+		if expect != "" {
+			mismatches := compareCommands(expected, commands)
+			for _, m := range mismatches {
+				cli.Error(m)
+			}
+			if len(mismatches) > 0 {
+				os.Exit(1)
+			}
+			cli.Success("All %d commands match the expected commands.", len(expected))
+		}
+
+		if runErr != nil {
+			abort(runErr)
+		}
+	},
+}
+
+type replayServer struct {
+	info   server.GameInfo
+	cge    string
+	events []recordedEvent
+	speed  float64
+	linger time.Duration
+	gameId string
+
+	lock      sync.Mutex
+	players   map[string]string
+	commands  []wsMessage
+	sent      int
+	connected bool
+}
+
+var replayUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+func (r *replayServer) handler() http.Handler {
+	r.players = make(map[string]string)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/info", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, r.info)
+	})
+	mux.HandleFunc("/api/events", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(r.cge))
+	})
+	mux.HandleFunc("/api/games", func(w http.ResponseWriter, req *http.Request) {
+		type game struct {
+			Id        string `json:"id"`
+			Players   int    `json:"players"`
+			Protected bool   `json:"protected"`
+		}
+		switch req.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]any{
+				"private": 0,
+				"public":  []game{{Id: r.gameId, Players: r.playerCount()}},
+			})
+		case http.MethodPost:
+			writeJSON(w, http.StatusCreated, map[string]any{
+				"game_id": r.gameId,
+			})
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/api/games/", func(w http.ResponseWriter, req *http.Request) {
+		parts := strings.Split(strings.Trim(strings.TrimPrefix(req.URL.Path, "/api/games/"), "/"), "/")
+		if parts[0] != r.gameId {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "game not found"})
+			return
+		}
+		switch {
+		case len(parts) == 1:
+			writeJSON(w, http.StatusOK, map[string]any{
+				"id":        r.gameId,
+				"players":   r.playerCount(),
+				"protected": false,
+			})
+		case len(parts) == 2 && parts[1] == "players":
+			r.handlePlayers(w, req)
+		case len(parts) == 2 && (parts[1] == "connect" || parts[1] == "spectate"):
+			r.handleConnect(w, req)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	return mux
+}
+
+func (r *replayServer) handlePlayers(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		writeJSON(w, http.StatusOK, map[string]any{"players": r.players})
+	case http.MethodPost:
+		type request struct {
+			Username string `json:"username"`
+		}
+		var data request
+		err := json.NewDecoder(req.Body).Decode(&data)
+		if err != nil || data.Username == "" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request"})
+			return
+		}
+		playerId := uuid.NewString()
+		r.lock.Lock()
+		r.players[playerId] = data.Username
+		r.lock.Unlock()
+		writeJSON(w, http.StatusCreated, map[string]string{
+			"player_id":     playerId,
+			"player_secret": uuid.NewString(),
+		})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// handleConnect replays the recording to the first websocket connection. All later connections are rejected.
+func (r *replayServer) handleConnect(w http.ResponseWriter, req *http.Request) {
+	r.lock.Lock()
+	if r.connected {
+		r.lock.Unlock()
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "the recording has already been replayed"})
+		return
+	}
+	r.connected = true
+	r.lock.Unlock()
+
+	conn, err := replayUpgrader.Upgrade(w, req, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			var msg wsMessage
+			err := conn.ReadJSON(&msg)
+			if err != nil {
+				return
+			}
+			r.lock.Lock()
+			r.commands = append(r.commands, msg)
+			r.lock.Unlock()
+		}
+	}()
+
+	var last int64
+	for _, e := range r.events {
+		if r.speed > 0 && e.Time > last {
+			select {
+			case <-time.After(time.Duration(float64(e.Time-last)/r.speed) * time.Millisecond):
+			case <-closed:
+				return
+			}
+		}
+		last = e.Time
+		err = conn.WriteJSON(wsMessage{
+			Name: e.Name,
+			Data: e.Data,
+		})
+		if err != nil {
+			return
+		}
+		r.lock.Lock()
+		r.sent++
+		r.lock.Unlock()
+	}
+
+	select {
+	case <-time.After(r.linger):
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "end of recording"))
+		select {
+		case <-time.After(time.Second):
+		case <-closed:
+		}
+	case <-closed:
+	}
+}
+
+// runClient runs the client of the project in root in a temporary copy of the project, which points to the replay server at url.
+// The .codegame.json file of the project itself is never modified.
+func (r *replayServer) runClient(root string, data *cgfile.CodeGameFileData, url string, args []string) error {
+	dir, err := os.MkdirTemp("", "codegame-replay-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	err = mirrorProject(root, dir)
+	if err != nil {
+		return fmt.Errorf("failed to prepare project: %w", err)
+	}
+	replayData := *data
+	replayData.URL = url
+	err = replayData.Write(dir)
+	if err != nil {
+		return err
+	}
+
+	// The client receives the interrupt signal as well. Ignore it until the client has exited to remove the temporary copy.
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	err = os.Chdir(dir)
+	if err != nil {
+		return err
+	}
+	defer os.Chdir(wd)
+
+	if containsString(nativeLanguages, data.Lang) {
+		return runNative(&replayData, args)
+	}
+	return modules.ExecuteRun(modules.RunData{
+		Lang: data.Lang,
+		Args: args,
+	}, &replayData)
+}
+
+// mirrorProject links all top-level files and directories of the project in src except .codegame.json into dst.
+// Entries, which cannot be linked, are copied.
+func mirrorProject(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Name() == ".codegame.json" {
+			continue
+		}
+		source := filepath.Join(src, entry.Name())
+		target := filepath.Join(dst, entry.Name())
+		if os.Symlink(source, target) == nil {
+			continue
+		}
+		if entry.IsDir() {
+			err = copyTree(source, target)
+		} else {
+			var content []byte
+			content, err = os.ReadFile(source)
+			if err == nil {
+				err = os.WriteFile(target, content, 0o644)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *replayServer) playerCount() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return len(r.players)
+}
+
+func (r *replayServer) sentEvents() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.sent
+}
+
+func (r *replayServer) capturedCommands() []wsMessage {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]wsMessage{}, r.commands...)
+}
+
+// loadCommands reads a file consisting of one JSON encoded command per line.
+func loadCommands(filename string) ([]wsMessage, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	commands := make([]wsMessage, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var cmd wsMessage
+		err = json.Unmarshal(scanner.Bytes(), &cmd)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		commands = append(commands, cmd)
+	}
+	return commands, scanner.Err()
+}
+
+func writeCommands(filename string, commands []wsMessage) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, c := range commands {
+		err = encoder.Encode(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compareCommands returns a description of every difference between expected and actual.
+func compareCommands(expected, actual []wsMessage) []string {
+	mismatches := make([]string, 0)
+	for i := 0; i < len(expected) || i < len(actual); i++ {
+		if i >= len(actual) {
+			mismatches = append(mismatches, fmt.Sprintf("command %d: expected '%s', got nothing", i+1, expected[i].Name))
+			continue
+		}
+		if i >= len(expected) {
+			mismatches = append(mismatches, fmt.Sprintf("command %d: unexpected command '%s'", i+1, actual[i].Name))
+			continue
+		}
+		if expected[i].Name != actual[i].Name {
+			mismatches = append(mismatches, fmt.Sprintf("command %d: expected '%s', got '%s'", i+1, expected[i].Name, actual[i].Name))
+			continue
+		}
+		if !jsonEqual(expected[i].Data, actual[i].Data) {
+			mismatches = append(mismatches, fmt.Sprintf("command %d ('%s'): expected data %s, got %s", i+1, expected[i].Name, string(expected[i].Data), string(actual[i].Data)))
+		}
+	}
+	return mismatches
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	var valueA, valueB any
+	if len(a) > 0 && json.Unmarshal(a, &valueA) != nil {
+		return false
+	}
+	if len(b) > 0 && json.Unmarshal(b, &valueB) != nil {
+		return false
+	}
+	return reflect.DeepEqual(valueA, valueB)
+}
+
+func init() {
+	replayCmd.AddCommand(replayServeCmd)
+	replayServeCmd.Flags().Float64P("speed", "s", 1, "The playback speed of the recording. (0: send all events immediately)")
+	replayServeCmd.Flags().DurationP("linger", "", 2*time.Second, "How long to wait for further commands after the last event before closing the connection.")
+	replayServeCmd.Flags().StringP("output", "o", "", "Write the captured commands to this file. (one JSON object per line)")
+	replayServeCmd.Flags().StringP("expect", "e", "", "Compare the captured commands with the commands in this file and fail on mismatch.")
+}
@@ -28,10 +28,19 @@ func Execute(version string) {
 	rootCmd.Version = version
 	rootCmd.InitDefaultVersionFlag()
 
-	if len(os.Args) <= 1 || (os.Args[1] != "upgrade" && os.Args[1] != "uninstall") {
+	// Completion requests (__complete, __completeNoDesc) are run by the shell on every <TAB> and must not
+	// print warnings or wait for the network.
+	completing := len(os.Args) > 1 && strings.HasPrefix(os.Args[1], "__complete")
+
+	if !completing && (len(os.Args) <= 1 || (os.Args[1] != "upgrade" && os.Args[1] != "uninstall")) {
 		versionCheck(true, false)
 	}
 
+	initCompletionCmd()
+	if !completing {
+		registerPlugins()
+	}
+
 	err := rootCmd.Execute()
 	if err != nil {
 		os.Exit(1)
@@ -84,10 +93,6 @@ func getLatestVersion(ignoreCache bool) (string, error) {
 	return tag, nil
 }
 
-func init() {
-	rootCmd.CompletionOptions.HiddenDefaultCmd = true
-}
-
 // abort prints the error to the console and terminates the program.
 // abort does nothing if err is nil.
 func abort(err error) {
@@ -17,11 +17,20 @@ import (
 
 // runCmd represents the run command
 var runCmd = &cobra.Command{
-	Use:                "run",
-	Short:              "Run the current project.",
+	Use:   "run [--workspace] [args...]",
+	Short: "Run the current project.",
+	Long: `Run the current project with the specified arguments.
+
+With --workspace (-w) as the first argument the server of the current workspace is started on the dev port
+and all clients are run with the remaining arguments as soon as the server is ready.`,
 	DisableFlagParsing: true,
 	Args:               cobra.ArbitraryArgs,
 	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 && (args[0] == "--workspace" || args[0] == "-w") {
+			runWorkspace(args[1:])
+			return
+		}
+
 		root, err := cgfile.FindProjectRoot()
 		abort(err)
 		err = os.Chdir(root)
@@ -33,30 +42,14 @@ var runCmd = &cobra.Command{
 		abort(data.Write(""))
 
 		if data.GameVersion != "" {
-			wrapMaj, wrapMin, _, err := semver.ParseVersion(data.GameVersion)
-			if err != nil {
-				goto skipGameVersionCheck
-			}
-
 			api, err := server.NewAPI(data.URL)
-			if err != nil {
-				goto skipGameVersionCheck
-			}
-			info, err := api.FetchGameInfo()
-			if err != nil || info.Version == "" {
-				goto skipGameVersionCheck
-			}
-
-			gameMaj, gameMin, _, err := semver.ParseVersion(info.Version)
-			if err != nil {
-				goto skipGameVersionCheck
-			}
-
-			if wrapMaj != gameMaj || wrapMin != gameMin {
-				cli.Warn("Game version mismatch. Server: v%s, client: v%s. Please run 'codegame update'.", info.Version, data.GameVersion)
+			if err == nil {
+				info, err := api.FetchGameInfo()
+				if err == nil && gameVersionMismatch(data.GameVersion, info.Version) {
+					cli.Warn("Game version mismatch. Server: v%s, client: v%s. Please run 'codegame update'.", info.Version, data.GameVersion)
+				}
 			}
 		}
-	skipGameVersionCheck:
 
 		runData := modules.RunData{
 			Lang: data.Lang,
@@ -69,16 +62,46 @@ var runCmd = &cobra.Command{
 			os.Setenv("CG_PORT", fmt.Sprintf("%d", port))
 		}
 
+		if session, ok := findActiveSession(); ok && data.Type == "client" && external.TrimURL(session.GameURL) != data.URL {
+			cli.Warn("The active session %s@%s belongs to a different game than the project (%s). Not using it.", session.Username, session.GameURL, data.URL)
+		} else if ok && data.Type == "client" {
+			for name, value := range map[string]string{
+				"CG_GAME_ID":       session.GameId,
+				"CG_PLAYER_ID":     session.PlayerId,
+				"CG_PLAYER_SECRET": session.PlayerSecret,
+			} {
+				if _, ok := os.LookupEnv(name); !ok {
+					os.Setenv(name, value)
+				}
+			}
+		}
+
 		switch data.Lang {
 		case "cs", "go", "java", "js", "ts":
 			err = modules.ExecuteRun(runData, data)
 			abort(err)
+		case "py", "rs":
+			abort(runNative(data, args))
 		default:
 			abort(fmt.Errorf("'run' is not supported for '%s'", data.Lang))
 		}
 	},
 }
 
+// gameVersionMismatch returns true if the major or minor versions of clientVersion and serverVersion differ.
+// Invalid versions are never considered a mismatch.
+func gameVersionMismatch(clientVersion, serverVersion string) bool {
+	clientMaj, clientMin, _, err := semver.ParseVersion(clientVersion)
+	if err != nil {
+		return false
+	}
+	serverMaj, serverMin, _, err := semver.ParseVersion(serverVersion)
+	if err != nil || serverVersion == "" {
+		return false
+	}
+	return clientMaj != serverMaj || clientMin != serverMin
+}
+
 func findAvailablePort(port int) int {
 	for i := port; i < port+100; i++ {
 		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", i))
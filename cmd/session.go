@@ -1,12 +1,21 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/Bananenpro/cli"
+	"github.com/adrg/xdg"
 	"github.com/code-game-project/go-utils/sessions"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 // sessionCmd represents the session command
@@ -15,55 +24,253 @@ var sessionCmd = &cobra.Command{
 	Short: "Manage CodeGame sessions.",
 }
 
+// selectSession returns the session specified by args, which may contain a game URL and a username,
+// a single 'username@url' or a pattern (see matchSession) matching exactly one session.
+// If args are missing or ambiguous, the user is asked to select a session, but only if stdin is a terminal.
 func selectSession(args []string) (sessions.Session, error) {
-	var gameURL string
-	var username string
-	if len(args) > 0 {
-		gameURL = args[0]
-		if len(args) > 1 {
-			username = args[1]
+	var pattern string
+	switch len(args) {
+	case 0:
+		pattern = "*"
+	case 1:
+		pattern = args[0]
+	default:
+		pattern = args[1] + "@" + args[0]
+	}
+
+	candidates, err := findSessions([]string{pattern})
+	if err != nil {
+		return sessions.Session{}, err
+	}
+	if len(candidates) == 0 {
+		if len(args) == 0 {
+			return sessions.Session{}, errNoSessions()
 		}
+		return sessions.Session{}, fmt.Errorf("no session matches '%s'", pattern)
 	}
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+	if !isInteractive() {
+		if len(args) == 0 {
+			return sessions.Session{}, errors.New("no session specified (use 'username@url')")
+		}
+		return sessions.Session{}, fmt.Errorf("'%s' matches %d sessions (use 'username@url')", pattern, len(candidates))
+	}
+	return promptSession(candidates)
+}
 
-	if gameURL == "" {
-		urls, err := sessions.ListGames()
-		if len(urls) == 0 {
-			abort(errors.New("no sessions available"))
+// selectSessions returns all sessions matching any of patterns and gameURL, which may be a pattern itself.
+// If all is false, exactly one session is selected with selectSession.
+func selectSessions(patterns []string, all bool, gameURL string) ([]sessions.Session, error) {
+	if !all {
+		if gameURL != "" {
+			patterns = append([]string{gameURL}, patterns...)
 		}
+		session, err := selectSession(patterns)
 		if err != nil {
-			gameURL, err = cli.Input("Game URL:")
-		} else {
-			var index int
-			index, err = cli.Select("Game URL:", urls)
-			if err == nil {
-				gameURL = urls[index]
+			return nil, err
+		}
+		return []sessions.Session{session}, nil
+	}
+
+	if len(patterns) == 0 {
+		patterns = []string{"*"}
+	}
+	candidates, err := findSessions(patterns)
+	if err != nil {
+		return nil, err
+	}
+	selected := make([]sessions.Session, 0, len(candidates))
+	for _, s := range candidates {
+		if gameURL == "" || matchGlob(gameURL, s.GameURL) {
+			selected = append(selected, s)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, errNoSessions()
+	}
+	return selected, nil
+}
+
+// findSessions returns all sessions matching any of patterns sorted by game URL and username.
+func findSessions(patterns []string) ([]sessions.Session, error) {
+	all, err := listAllSessions()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]sessions.Session, 0, len(all))
+	for _, s := range all {
+		for _, pattern := range patterns {
+			if matchSession(pattern, s) {
+				result = append(result, s)
+				break
 			}
 		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].GameURL != result[j].GameURL {
+			return result[i].GameURL < result[j].GameURL
+		}
+		return result[i].Username < result[j].Username
+	})
+	return result, nil
+}
+
+// promptSession asks the user to select one of candidates, which must be sorted by game URL.
+func promptSession(candidates []sessions.Session) (sessions.Session, error) {
+	urls := make([]string, 0)
+	for _, s := range candidates {
+		if len(urls) == 0 || urls[len(urls)-1] != s.GameURL {
+			urls = append(urls, s.GameURL)
+		}
+	}
+
+	gameURL := urls[0]
+	if len(urls) > 1 {
+		index, err := cli.Select("Game URL:", urls)
 		if err != nil {
-			os.Exit(0)
+			return sessions.Session{}, err
 		}
+		gameURL = urls[index]
 	}
 
-	if username == "" {
-		usernames, err := sessions.ListUsernames(gameURL)
-		if len(usernames) == 0 {
-			abort(errors.New("no sessions available"))
+	matching := make([]sessions.Session, 0, len(candidates))
+	usernames := make([]string, 0, len(candidates))
+	for _, s := range candidates {
+		if s.GameURL == gameURL {
+			matching = append(matching, s)
+			usernames = append(usernames, s.Username)
 		}
-		if err != nil {
-			username, err = cli.Input("Username:")
-		} else {
-			var index int
-			index, err = cli.Select("Username:", usernames)
-			if err == nil {
-				username = usernames[index]
-			}
+	}
+	if len(matching) == 1 {
+		return matching[0], nil
+	}
+	index, err := cli.Select("Username:", usernames)
+	if err != nil {
+		return sessions.Session{}, err
+	}
+	return matching[index], nil
+}
+
+// isInteractive returns true if stdin is a terminal.
+func isInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// listAllSessions loads every session in the session store.
+func listAllSessions() ([]sessions.Session, error) {
+	sessionList, err := sessions.ListSessions()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
 		}
-		if err != nil {
-			os.Exit(0)
+		return nil, err
+	}
+
+	result := make([]sessions.Session, 0, len(sessionList))
+	for gameURL, usernames := range sessionList {
+		for _, username := range usernames {
+			session, err := sessions.LoadSession(gameURL, username)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load %s@%s: %w", username, gameURL, err)
+			}
+			result = append(result, session)
 		}
 	}
+	return result, nil
+}
 
-	return sessions.LoadSession(gameURL, username)
+// errNoSessions returns an error stating that no sessions are available, which hints at 'session unlock' if the session store is locked.
+func errNoSessions() error {
+	if sessionStoreLocked() {
+		return errors.New("no sessions available (the session store is locked, run 'codegame session unlock')")
+	}
+	return errors.New("no sessions available")
+}
+
+// matchSession reports whether session matches pattern.
+// Patterns have the form 'username@url' or 'url', where '*' matches any sequence of characters and '?' matches any single character.
+func matchSession(pattern string, session sessions.Session) bool {
+	if userPattern, urlPattern, ok := strings.Cut(pattern, "@"); ok {
+		return matchGlob(userPattern, session.Username) && matchGlob(urlPattern, session.GameURL)
+	}
+	return matchGlob(pattern, session.GameURL)
+}
+
+func matchGlob(pattern, value string) bool {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	matched, err := regexp.MatchString("^"+expr+"$", value)
+	return err == nil && matched
+}
+
+// usernameRegex matches usernames, which can be safely used as file names in the session store.
+// Dots are only allowed between other characters, so that '.' and '..' are rejected.
+var usernameRegex = regexp.MustCompile(`^[^/\\\x00-\x1f.]+(\.[^/\\\x00-\x1f.]+)*$`)
+
+const usernameRegexMessage = "Usernames must not contain slashes, backslashes, control characters or consecutive dots and must not start or end with a dot."
+
+func validateUsername(username string) error {
+	if !usernameRegex.MatchString(username) {
+		return fmt.Errorf("invalid username '%s'. %s", username, usernameRegexMessage)
+	}
+	return nil
+}
+
+// sessionsDir is the directory of the session store. It contains a directory for every game URL.
+var sessionsDir = filepath.Join(xdg.DataHome, "codegame", "games")
+
+// sessionFilePath returns the path of the file in which the session for username of gameURL is stored.
+func sessionFilePath(gameURL, username string) string {
+	return filepath.Join(sessionsDir, url.PathEscape(gameURL), username+".json")
+}
+
+// writeSessionFile stores s in the session store like sessions.Session.Save, but only readable by the current user.
+func writeSessionFile(s sessions.Session) error {
+	if s.GameURL == "" {
+		return errors.New("empty game url")
+	}
+	path := sessionFilePath(s.GameURL, s.Username)
+	err := os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(path, data, 0o600)
+	if err != nil {
+		return err
+	}
+	// WriteFile keeps the permissions of existing files.
+	return os.Chmod(path, 0o600)
+}
+
+// sessionExists returns true if a session for username exists in the session store of gameURL.
+func sessionExists(gameURL, username string) bool {
+	_, err := sessions.LoadSession(gameURL, username)
+	return err == nil
+}
+
+// replaceSession saves newSession and removes oldSession afterwards if they are stored at different locations.
+// It fails if a different session already exists at the location of newSession and force is false.
+func replaceSession(oldSession, newSession sessions.Session, force bool) error {
+	moved := oldSession.GameURL != newSession.GameURL || oldSession.Username != newSession.Username
+	if moved && !force && sessionExists(newSession.GameURL, newSession.Username) {
+		return fmt.Errorf("session %s@%s already exists (use --force to overwrite it)", newSession.Username, newSession.GameURL)
+	}
+
+	err := saveSession(newSession)
+	if err != nil {
+		return err
+	}
+	if moved {
+		return oldSession.Remove()
+	}
+	return nil
 }
 
 func init() {
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Bananenpro/cli"
+	"github.com/code-game-project/go-utils/external"
+	"github.com/code-game-project/go-utils/sessions"
+	"github.com/spf13/cobra"
+)
+
+// sessionCopyCmd represents the session copy command
+var sessionCopyCmd = &cobra.Command{
+	Use:   "copy <from-url> <to-url> [username...]",
+	Short: "Copy sessions to a different game URL.",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		force, err := cmd.Flags().GetBool("force")
+		abort(err)
+
+		fromURL := external.TrimURL(args[0])
+		toURL := external.TrimURL(args[1])
+		if fromURL == toURL {
+			abort(fmt.Errorf("source and destination are the same"))
+		}
+
+		usernames := args[2:]
+		if len(usernames) == 0 {
+			usernames, err = sessions.ListUsernames(fromURL)
+			if err != nil || len(usernames) == 0 {
+				abort(fmt.Errorf("no sessions available for %s", fromURL))
+			}
+		}
+
+		failed := false
+		for _, username := range usernames {
+			session, err := sessions.LoadSession(fromURL, username)
+			if err != nil {
+				cli.Error("Failed to load session %s@%s: %s", username, fromURL, err)
+				failed = true
+				continue
+			}
+			if !force && sessionExists(toURL, username) {
+				cli.Error("Session %s@%s already exists (use --force to overwrite it).", username, toURL)
+				failed = true
+				continue
+			}
+			session.GameURL = toURL
+			err = saveSession(session)
+			if err != nil {
+				cli.Error("Failed to save session %s@%s: %s", username, toURL, err)
+				failed = true
+				continue
+			}
+			cli.Success("Copied %s@%s to %s@%s.", username, fromURL, username, toURL)
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	sessionCmd.AddCommand(sessionCopyCmd)
+	sessionCopyCmd.Flags().BoolP("force", "f", false, "Overwrite existing sessions at the destination.")
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"github.com/Bananenpro/cli"
+	"github.com/spf13/cobra"
+)
+
+// sessionEditCmd represents the session edit command
+var sessionEditCmd = &cobra.Command{
+	Use:               "edit",
+	Short:             "Change the username or secrets of a session.",
+	Args:              cobra.RangeArgs(0, 2),
+	ValidArgsFunction: completeSession,
+	Run: func(cmd *cobra.Command, args []string) {
+		force, err := cmd.Flags().GetBool("force")
+		abort(err)
+
+		session, err := selectSession(args)
+		abortf("Failed to load session: %s", err)
+
+		edited := session
+		fields := []struct {
+			flag   string
+			prompt string
+			value  *string
+			secret bool
+		}{
+			{flag: "username", prompt: "Username", value: &edited.Username},
+			{flag: "game-id", prompt: "Game ID", value: &edited.GameId},
+			{flag: "player-id", prompt: "Player ID", value: &edited.PlayerId},
+			{flag: "player-secret", prompt: "Player secret", value: &edited.PlayerSecret, secret: true},
+		}
+
+		interactive := true
+		for _, f := range fields {
+			if cmd.Flags().Changed(f.flag) {
+				interactive = false
+				*f.value, err = cmd.Flags().GetString(f.flag)
+				abort(err)
+			}
+		}
+
+		if interactive {
+			for _, f := range fields {
+				var value string
+				if f.secret {
+					// Neither show the current secret nor echo the new one.
+					value, err = readPassword(f.prompt + " (leave empty to keep the current secret):")
+				} else {
+					value, err = cli.InputOptional(f.prompt + " (leave empty to keep '" + *f.value + "'):")
+				}
+				abort(err)
+				if value != "" {
+					*f.value = value
+				}
+			}
+		}
+
+		if edited == session {
+			cli.Print("Nothing changed.")
+			return
+		}
+
+		err = replaceSession(session, edited, force)
+		abortf("Failed to save session: %s", err)
+		cli.Success("Successfully updated %s@%s.", edited.Username, edited.GameURL)
+	},
+}
+
+func init() {
+	sessionCmd.AddCommand(sessionEditCmd)
+	sessionEditCmd.Flags().StringP("username", "", "", "The new username.")
+	sessionEditCmd.Flags().StringP("game-id", "", "", "The new game ID.")
+	sessionEditCmd.Flags().StringP("player-id", "", "", "The new player ID.")
+	sessionEditCmd.Flags().StringP("player-secret", "", "", "The new player secret.")
+	sessionEditCmd.Flags().BoolP("force", "f", false, "Overwrite an existing session when changing the username.")
+}
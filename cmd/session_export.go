@@ -1,19 +1,119 @@
 package cmd
 
 import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Bananenpro/cli"
 	"github.com/spf13/cobra"
 )
 
 // sessionExportCmd represents the session export command
 var sessionExportCmd = &cobra.Command{
 	Use:   "export",
-	Short: "Export a session to CodeGame Share (same as 'codegame share session').",
-	Args:  cobra.RangeArgs(0, 2),
+	Short: "Export a session to CodeGame Share (same as 'codegame share session') or to a file.",
+	Long: `Export a session to CodeGame Share (same as 'codegame share session').
+
+With --file one or many sessions are written to a file instead, which can be imported with 'codegame session import --file'.
+The sessions can be selected with patterns like 'username@url', '*@game.example.com' or 'game.example.com'.`,
+	Args:              cobra.ArbitraryArgs,
+	ValidArgsFunction: completeSession,
 	Run: func(cmd *cobra.Command, args []string) {
-		shareSessionCmd.Run(cmd, args)
+		filename, err := cmd.Flags().GetString("file")
+		abort(err)
+		if filename == "" {
+			if len(args) > 2 {
+				abort(errors.New("patterns are only supported with --file"))
+			}
+			shareSessionCmd.Run(cmd, args)
+			return
+		}
+
+		encrypt, err := cmd.Flags().GetBool("encrypt")
+		abort(err)
+
+		selected, err := selectSessions(args, len(args) > 0, "")
+		abortf("Failed to select sessions: %s", err)
+
+		bundle := sessionBundle{
+			Format: sessionBundleFormat,
+		}
+		list := make([]storedSession, len(selected))
+		for i, s := range selected {
+			list[i] = newStoredSession(s)
+		}
+		if encrypt {
+			passphrase, err := readNewPassphrase()
+			abort(err)
+			bundle.Encrypted = &encryptedData{
+				KDF:  kdfScrypt,
+				Salt: make([]byte, 16),
+			}
+			_, err = rand.Read(bundle.Encrypted.Salt)
+			abort(err)
+			key, err := deriveKey(passphrase, bundle.Encrypted.Salt)
+			abort(err)
+			err = bundle.Encrypted.sealSessions(key, list)
+			abortf("Failed to encrypt sessions: %s", err)
+		} else {
+			bundle.Sessions = list
+		}
+
+		data, err := json.MarshalIndent(bundle, "", "  ")
+		abort(err)
+		err = os.WriteFile(filename, data, 0o600)
+		abortf("Failed to write file: %s", err)
+
+		for _, s := range selected {
+			cli.Print("  - %s@%s", s.Username, s.GameURL)
+		}
+		cli.Success("Successfully exported %d sessions to '%s'.", len(selected), filename)
 	},
 }
 
+const sessionBundleFormat = "codegame-sessions"
+
+// sessionBundle is the content of a file created by 'codegame session export --file'.
+type sessionBundle struct {
+	Format    string          `json:"format"`
+	Sessions  []storedSession `json:"sessions,omitempty"`
+	Encrypted *encryptedData  `json:"encrypted,omitempty"`
+}
+
+// loadSessionBundle reads a session bundle from filename and asks for the passphrase if it is encrypted.
+func loadSessionBundle(filename string) ([]storedSession, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var bundle sessionBundle
+	err = json.Unmarshal(data, &bundle)
+	if err != nil || bundle.Format != sessionBundleFormat {
+		return nil, fmt.Errorf("'%s' is not a session file", filename)
+	}
+
+	if bundle.Encrypted == nil {
+		return bundle.Sessions, nil
+	}
+	passphrase, err := readPassword("Passphrase:")
+	if err != nil {
+		return nil, err
+	}
+	key, err := deriveKey(passphrase, bundle.Encrypted.Salt)
+	if err != nil {
+		return nil, err
+	}
+	return bundle.Encrypted.openSessions(key)
+}
+
 func init() {
 	sessionCmd.AddCommand(sessionExportCmd)
+	sessionExportCmd.Flags().StringP("file", "f", "", "Write the sessions to this file instead of uploading them to CodeGame Share.")
+	sessionExportCmd.Flags().BoolP("encrypt", "e", false, "Encrypt the file with a passphrase.")
+	sessionExportCmd.Flags().BoolP("qr", "", false, "Print the link as a QR code.")
+	sessionExportCmd.Flags().StringP("qr-png", "", "", "Write the link as a QR code to this PNG file.")
 }
@@ -1,13 +1,15 @@
 package cmd
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
-	"time"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
 
 	"github.com/Bananenpro/cli"
-	"github.com/code-game-project/go-utils/config"
+	"github.com/code-game-project/codegame-cli/share"
 	"github.com/code-game-project/go-utils/external"
 	"github.com/code-game-project/go-utils/sessions"
 	"github.com/spf13/cobra"
@@ -16,11 +18,26 @@ import (
 // sessionImportCmd represents the session import command
 var sessionImportCmd = &cobra.Command{
 	Use:   "import",
-	Short: "Import a session from CodeGame Share.",
-	Args:  cobra.RangeArgs(0, 1),
+	Short: "Import a session from CodeGame Share or from a file.",
+	Long: `Import a session from CodeGame Share.
+
+With --file the sessions are imported from a file created with 'codegame session export --file' instead.
+Patterns like 'username@url', '*@game.example.com' or 'game.example.com' can be used to only import some of the sessions in the file.`,
+	Args: cobra.ArbitraryArgs,
 	Run: func(cmd *cobra.Command, args []string) {
+		filename, err := cmd.Flags().GetString("file")
+		abort(err)
+		if filename != "" {
+			force, err := cmd.Flags().GetBool("force")
+			abort(err)
+			importSessionFile(filename, args, force)
+			return
+		}
+		if len(args) > 1 {
+			abort(errors.New("patterns are only supported with --file"))
+		}
+
 		var id string
-		var err error
 		if len(args) > 0 {
 			id = args[0]
 		} else {
@@ -29,31 +46,9 @@ var sessionImportCmd = &cobra.Command{
 				return
 			}
 		}
-
-		conf := config.Load()
-		shareURL := external.TrimURL(conf.ShareURL)
-		baseURL := external.BaseURL("http", external.IsTLS(shareURL), shareURL)
-
-		resp, err := http.Get(fmt.Sprintf(baseURL+"/%s?type=session", id))
-		abortf(fmt.Sprintf("Failed to contact %s: %s", conf.ShareURL, "%s"), err)
-
-		for resp.StatusCode == http.StatusForbidden {
-			password, err := cli.Input("Password:")
-			abort(err)
-			request, err := http.NewRequest("GET", fmt.Sprintf(baseURL+"/%s?type=session", id), nil)
-			abort(err)
-			request.Header.Set("Password", password)
-			resp, err = http.DefaultClient.Do(request)
-			abortf(fmt.Sprintf("Failed to contact %s: %s", conf.ShareURL, "%s"), err)
-			if resp.StatusCode == http.StatusTooManyRequests {
-				time.Sleep(1 * time.Second)
-				resp, err = http.DefaultClient.Do(request)
-				abortf(fmt.Sprintf("Failed to contact %s: %s", conf.ShareURL, "%s"), err)
-			}
-			if resp.StatusCode != http.StatusForbidden {
-				break
-			}
-			cli.Error("Wrong password. Try again.")
+		// Accept share links like the ones encoded in QR codes by 'codegame share session --qr'.
+		if strings.Contains(id, "/") {
+			id = path.Base(strings.TrimSuffix(id, "/"))
 		}
 
 		type resSession struct {
@@ -63,29 +58,111 @@ var sessionImportCmd = &cobra.Command{
 		}
 
 		type response struct {
-			Error    string     `json:"error"`
 			GameURL  string     `json:"game_url"`
 			Username string     `json:"username"`
 			Session  resSession `json:"session"`
 		}
 
+		client := newShareClient("")
 		var data response
-		err = json.NewDecoder(resp.Body).Decode(&data)
-		if err != nil {
-			abortf("Failed to decode server response: %s", err)
-		}
-		if data.Error != "" {
-			abort(fmt.Errorf(data.Error))
+		err = withSharePassword(func(password string) error {
+			return client.Get(share.TypeSession, id, password, &data)
+		})
+		if errors.Is(err, share.ErrNotFound) {
+			abort(fmt.Errorf("session '%s' does not exist", id))
 		}
+		abortf(fmt.Sprintf("Failed to import session from %s: %s", client.BaseURL(), "%s"), err)
 
-		session := sessions.NewSession(data.GameURL, data.Username, data.Session.GameId, data.Session.PlayerId, data.Session.PlayerSecret)
-		err = session.Save()
+		session, err := sanitizeImportedSession(sessions.NewSession(data.GameURL, data.Username, data.Session.GameId, data.Session.PlayerId, data.Session.PlayerSecret))
+		abortf("Invalid session: %s", err)
+		err = saveSession(session)
 		abortf("Failed to save session: %s", err)
 
 		cli.Success("Successfully imported %s@%s!", session.Username, session.GameURL)
 	},
 }
 
+func importSessionFile(filename string, patterns []string, force bool) {
+	list, err := loadSessionBundle(filename)
+	abortf("Failed to load sessions: %s", err)
+
+	imported := 0
+	failed := false
+	for _, s := range list {
+		session, err := sanitizeImportedSession(s.session())
+		if err != nil {
+			cli.Error("Skipping invalid session: %s", err)
+			failed = true
+			continue
+		}
+		if len(patterns) > 0 {
+			matched := false
+			for _, p := range patterns {
+				if matchSession(p, session) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				continue
+			}
+		}
+		if !force && sessionExists(session.GameURL, session.Username) {
+			cli.Error("Session %s@%s already exists (use --force to overwrite it).", session.Username, session.GameURL)
+			failed = true
+			continue
+		}
+		err = saveSession(session)
+		if err != nil {
+			cli.Error("Failed to save session %s@%s: %s", session.Username, session.GameURL, err)
+			failed = true
+			continue
+		}
+		cli.Print("  - %s@%s", session.Username, session.GameURL)
+		imported++
+	}
+
+	if imported > 0 {
+		cli.Success("Successfully imported %d sessions!", imported)
+	} else if !failed {
+		cli.Print("No matching sessions found.")
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// sanitizeImportedSession trims the game URL of s and rejects sessions whose game URL or username
+// cannot be used as a path in the session store without escaping the session directory.
+// Game URLs may contain a path ('/' is escaped by the session store), but no '.' or '..' segments or backslashes.
+func sanitizeImportedSession(s sessions.Session) (sessions.Session, error) {
+	s.GameURL = external.TrimURL(s.GameURL)
+	invalidURL := s.GameURL == "" || strings.ContainsAny(s.GameURL, `\`)
+	for _, segment := range strings.Split(s.GameURL, "/") {
+		if segment == "." || segment == ".." {
+			invalidURL = true
+		}
+	}
+	if invalidURL {
+		return s, fmt.Errorf("invalid game URL '%s'", s.GameURL)
+	}
+	if strings.Contains(s.Username, "..") || strings.ContainsAny(s.Username, `/\`) {
+		return s, fmt.Errorf("invalid username '%s'", s.Username)
+	}
+	if err := validateUsername(s.Username); err != nil {
+		return s, err
+	}
+
+	// The session file must be stored directly in the directory of its game URL inside of the session store.
+	rel, err := filepath.Rel(sessionsDir, sessionFilePath(s.GameURL, s.Username))
+	if parts := strings.Split(rel, string(filepath.Separator)); err != nil || len(parts) != 2 || parts[0] == ".." {
+		return s, fmt.Errorf("invalid game URL '%s'", s.GameURL)
+	}
+	return s, nil
+}
+
 func init() {
 	sessionCmd.AddCommand(sessionImportCmd)
+	sessionImportCmd.Flags().StringP("file", "f", "", "Import the sessions from a file created with 'codegame session export --file'.")
+	sessionImportCmd.Flags().BoolP("force", "", false, "Overwrite existing sessions when importing from a file.")
 }
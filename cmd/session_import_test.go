@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/code-game-project/go-utils/sessions"
+)
+
+func TestSanitizeImportedSession(t *testing.T) {
+	tests := []struct {
+		gameURL  string
+		username string
+		wantURL  string
+		wantErr  bool
+	}{
+		{"example.com", "alice", "example.com", false},
+		{"https://example.com/", "alice", "example.com", false},
+		{"localhost:8080", "alice", "localhost:8080", false},
+		{"example.com/games/foo", "alice", "example.com/games/foo", false},
+		{"", "alice", "", true},
+		{"..", "alice", "", true},
+		{"example.com/../foo", "alice", "", true},
+		{"example.com/./foo", "alice", "", true},
+		{`example.com\foo`, "alice", "", true},
+		{"example.com", "../../evil", "", true},
+		{"example.com", "a/b", "", true},
+		{"example.com", "..", "", true},
+	}
+	for _, test := range tests {
+		s, err := sanitizeImportedSession(sessions.Session{GameURL: test.gameURL, Username: test.username})
+		if (err != nil) != test.wantErr {
+			t.Errorf("sanitizeImportedSession(%q, %q) error = %v, want error: %t", test.gameURL, test.username, err, test.wantErr)
+			continue
+		}
+		if err == nil && s.GameURL != test.wantURL {
+			t.Errorf("sanitizeImportedSession(%q, %q) game URL = %q, want %q", test.gameURL, test.username, s.GameURL, test.wantURL)
+		}
+	}
+}
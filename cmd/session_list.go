@@ -22,6 +22,9 @@ var sessionListCmd = &cobra.Command{
 		if len(sessionList) == 0 {
 			cli.Print("No sessions stored.")
 		}
+		if sessionStoreLocked() {
+			cli.Warn("The session store is locked. Run 'codegame session unlock' to access the encrypted sessions.")
+		}
 	},
 }
 
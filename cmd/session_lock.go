@@ -0,0 +1,382 @@
+package cmd
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Bananenpro/cli"
+	"github.com/adrg/xdg"
+	"github.com/code-game-project/go-utils/sessions"
+	"github.com/mattn/go-colorable"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// sessionLockCmd represents the session lock command
+var sessionLockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Encrypt all stored sessions.",
+	Long: `Encrypt all stored sessions with a passphrase or a key stored in the CodeGame config directory.
+Locked sessions cannot be used until they are unlocked with 'codegame session unlock'.
+New sessions are added to the encrypted vault while the session store is locked.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		keyring, err := cmd.Flags().GetBool("keyring")
+		abort(err)
+
+		plain, err := listAllSessions()
+		abortf("Failed to load sessions: %s", err)
+
+		locked := make([]storedSession, 0)
+		var key []byte
+		var vault encryptedData
+		if sessionStoreLocked() {
+			vault, err = loadVault()
+			abortf("Failed to load session vault: %s", err)
+			key, err = vaultKey(vault)
+			abort(err)
+			locked, err = vault.openSessions(key)
+			abort(err)
+			keyring = vault.KDF == kdfKeyring
+		} else {
+			if len(plain) == 0 {
+				abort(errNoSessions())
+			}
+			if keyring {
+				key, err = loadKeyringKey(true)
+				abortf("Failed to load keyring key: %s", err)
+				vault.KDF = kdfKeyring
+			} else {
+				passphrase, err := readNewPassphrase()
+				abort(err)
+				vault.KDF = kdfScrypt
+				vault.Salt = make([]byte, 16)
+				_, err = rand.Read(vault.Salt)
+				abort(err)
+				key, err = deriveKey(passphrase, vault.Salt)
+				abort(err)
+			}
+		}
+
+		all := mergeSessions(locked, plain)
+		err = vault.sealSessions(key, all)
+		abortf("Failed to encrypt sessions: %s", err)
+		err = saveVault(vault)
+		abortf("Failed to save session vault: %s", err)
+
+		for _, s := range plain {
+			err = s.Remove()
+			if err != nil {
+				cli.Error("Failed to remove unencrypted session %s@%s: %s", s.Username, s.GameURL, err)
+			}
+		}
+
+		if keyring {
+			cli.Success("Successfully locked %d sessions with the key in %s.", len(all), keyringKeyPath)
+		} else {
+			cli.Success("Successfully locked %d sessions.", len(all))
+		}
+	},
+}
+
+const (
+	kdfScrypt  = "scrypt"
+	kdfKeyring = "keyring"
+)
+
+var (
+	sessionVaultPath = filepath.Join(xdg.DataHome, "codegame", "games.vault")
+	keyringKeyPath   = filepath.Join(xdg.ConfigHome, "codegame", "session.key")
+)
+
+// storedSession is the representation of a session in encrypted vaults and export bundles.
+type storedSession struct {
+	GameURL      string `json:"game_url"`
+	Username     string `json:"username"`
+	GameId       string `json:"game_id"`
+	PlayerId     string `json:"player_id"`
+	PlayerSecret string `json:"player_secret"`
+}
+
+func newStoredSession(s sessions.Session) storedSession {
+	return storedSession{
+		GameURL:      s.GameURL,
+		Username:     s.Username,
+		GameId:       s.GameId,
+		PlayerId:     s.PlayerId,
+		PlayerSecret: s.PlayerSecret,
+	}
+}
+
+func (s storedSession) session() sessions.Session {
+	return sessions.NewSession(s.GameURL, s.Username, s.GameId, s.PlayerId, s.PlayerSecret)
+}
+
+// mergeSessions combines a and b. Sessions in b replace sessions with the same game URL and username in a.
+func mergeSessions(a []storedSession, b []sessions.Session) []storedSession {
+	result := make([]storedSession, 0, len(a)+len(b))
+	index := make(map[string]int, len(a)+len(b))
+	for _, s := range a {
+		index[s.Username+"@"+s.GameURL] = len(result)
+		result = append(result, s)
+	}
+	for _, s := range b {
+		if i, ok := index[s.Username+"@"+s.GameURL]; ok {
+			result[i] = newStoredSession(s)
+			continue
+		}
+		index[s.Username+"@"+s.GameURL] = len(result)
+		result = append(result, newStoredSession(s))
+	}
+	return result
+}
+
+// encryptedData contains AES-GCM encrypted data and the information needed to derive its key.
+type encryptedData struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"`
+	Salt    []byte `json:"salt,omitempty"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+func (e *encryptedData) seal(key, plaintext []byte) error {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	e.Version = 1
+	e.Nonce = make([]byte, gcm.NonceSize())
+	_, err = rand.Read(e.Nonce)
+	if err != nil {
+		return err
+	}
+	e.Data = gcm.Seal(nil, e.Nonce, plaintext, nil)
+	return nil
+}
+
+func (e encryptedData) open(key []byte) ([]byte, error) {
+	if e.Version != 1 {
+		return nil, fmt.Errorf("unsupported encryption version: %d", e.Version)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, e.Nonce, e.Data, nil)
+	if err != nil {
+		return nil, errors.New("wrong passphrase or corrupted data")
+	}
+	return plaintext, nil
+}
+
+func (e *encryptedData) sealSessions(key []byte, list []storedSession) error {
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	return e.seal(key, data)
+}
+
+func (e encryptedData) openSessions(key []byte) ([]storedSession, error) {
+	data, err := e.open(key)
+	if err != nil {
+		return nil, err
+	}
+	var list []storedSession
+	err = json.Unmarshal(data, &list)
+	return list, err
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// vaultKey returns the key needed to decrypt e. It asks for the passphrase or loads the keyring key depending on e.KDF.
+func vaultKey(e encryptedData) ([]byte, error) {
+	switch e.KDF {
+	case kdfKeyring:
+		key, err := loadKeyringKey(false)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to load keyring key: %w", err)
+		}
+		return key, nil
+	case kdfScrypt:
+		passphrase, err := readPassword("Passphrase:")
+		if err != nil {
+			return nil, err
+		}
+		return deriveKey(passphrase, e.Salt)
+	default:
+		return nil, fmt.Errorf("unsupported key derivation function: %s", e.KDF)
+	}
+}
+
+// loadKeyringKey loads the key from the key file in the CodeGame config directory.
+// If create is true, a new random key is generated if the file does not exist.
+func loadKeyringKey(create bool) ([]byte, error) {
+	key, err := os.ReadFile(keyringKeyPath)
+	if err == nil {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("%s does not contain a valid key", keyringKeyPath)
+		}
+		return key, nil
+	}
+	if !create || !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	key = make([]byte, 32)
+	_, err = rand.Read(key)
+	if err != nil {
+		return nil, err
+	}
+	err = os.MkdirAll(filepath.Dir(keyringKeyPath), 0o700)
+	if err != nil {
+		return nil, err
+	}
+	return key, os.WriteFile(keyringKeyPath, key, 0o600)
+}
+
+// saveSession stores s in the session store. While the session store is locked, s is added to the vault instead
+// of being stored unencrypted. All commands, which create or modify sessions, must use saveSession instead of s.Save().
+func saveSession(s sessions.Session) error {
+	err := validateUsername(s.Username)
+	if err != nil {
+		return err
+	}
+	if !sessionStoreLocked() {
+		return s.Save()
+	}
+
+	vault, err := loadVault()
+	if err != nil {
+		return fmt.Errorf("failed to load session vault: %w", err)
+	}
+	key, err := cachedVaultKey(vault)
+	if err != nil {
+		return err
+	}
+	list, err := vault.openSessions(key)
+	if err != nil {
+		vaultKeyCache = nil
+		return err
+	}
+	err = vault.sealSessions(key, mergeSessions(list, []sessions.Session{s}))
+	if err != nil {
+		return err
+	}
+	err = saveVault(vault)
+	if err != nil {
+		return err
+	}
+	cli.Print("The session store is locked. %s@%s was added to the vault.", s.Username, s.GameURL)
+	return nil
+}
+
+// vaultKeyCache contains the vault key after it has been entered once, so that saving multiple sessions only asks for the passphrase once.
+var vaultKeyCache []byte
+
+func cachedVaultKey(vault encryptedData) ([]byte, error) {
+	if vaultKeyCache != nil {
+		return vaultKeyCache, nil
+	}
+	key, err := vaultKey(vault)
+	if err != nil {
+		return nil, err
+	}
+	vaultKeyCache = key
+	return key, nil
+}
+
+func sessionStoreLocked() bool {
+	_, err := os.Stat(sessionVaultPath)
+	return err == nil
+}
+
+func loadVault() (encryptedData, error) {
+	var vault encryptedData
+	data, err := os.ReadFile(sessionVaultPath)
+	if err != nil {
+		return vault, err
+	}
+	err = json.Unmarshal(data, &vault)
+	return vault, err
+}
+
+func saveVault(vault encryptedData) error {
+	data, err := json.Marshal(vault)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(sessionVaultPath), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(sessionVaultPath, data, 0o600)
+}
+
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// readPassword reads a line from stdin without echoing it if stdin is a terminal.
+func readPassword(prompt string) (string, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		line, err := stdinReader.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	out := colorable.NewColorableStdout()
+	fmt.Fprintf(out, "%s?%s %s ", cli.Green, cli.Reset, prompt)
+	password, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(out)
+	if err != nil {
+		return "", err
+	}
+	return string(password), nil
+}
+
+// readNewPassphrase asks for a new non-empty passphrase twice.
+func readNewPassphrase() (string, error) {
+	for {
+		passphrase, err := readPassword("Passphrase:")
+		if err != nil {
+			return "", err
+		}
+		if passphrase == "" {
+			cli.Error("The passphrase must not be empty.")
+			continue
+		}
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return passphrase, nil
+		}
+		confirmation, err := readPassword("Repeat passphrase:")
+		if err != nil {
+			return "", err
+		}
+		if passphrase == confirmation {
+			return passphrase, nil
+		}
+		cli.Error("The passphrases do not match. Try again.")
+	}
+}
+
+func init() {
+	sessionCmd.AddCommand(sessionLockCmd)
+	sessionLockCmd.Flags().BoolP("keyring", "k", false, "Use a random key stored in the CodeGame config directory instead of a passphrase.")
+}
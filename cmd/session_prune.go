@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bananenpro/cli"
+	"github.com/code-game-project/go-utils/server"
+	"github.com/code-game-project/go-utils/sessions"
+	"github.com/spf13/cobra"
+)
+
+// sessionPruneCmd represents the session prune command
+var sessionPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove sessions whose games or players no longer exist.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		abort(err)
+		unreachableToo, err := cmd.Flags().GetBool("unreachable-too")
+		abort(err)
+		yes, err := cmd.Flags().GetBool("yes")
+		abort(err)
+		olderThanStr, err := cmd.Flags().GetString("older-than")
+		abort(err)
+		var olderThan time.Duration
+		if olderThanStr != "" {
+			olderThan, err = parseAge(olderThanStr)
+			abort(err)
+		}
+
+		urls, err := sessions.ListGames()
+		if err != nil || len(urls) == 0 {
+			cli.Print("No sessions stored.")
+			return
+		}
+		sort.Strings(urls)
+
+		type staleSession struct {
+			session sessions.Session
+			reason  string
+		}
+		stale := make([]staleSession, 0)
+
+		cli.BeginLoading("Checking sessions...")
+		for _, gameURL := range urls {
+			usernames, err := sessions.ListUsernames(gameURL)
+			if err != nil {
+				continue
+			}
+
+			candidates := make([]sessions.Session, 0, len(usernames))
+			for _, username := range usernames {
+				if olderThan > 0 {
+					info, err := os.Stat(sessionFilePath(gameURL, username))
+					if err != nil || time.Since(info.ModTime()) < olderThan {
+						continue
+					}
+				}
+				session, err := sessions.LoadSession(gameURL, username)
+				if err != nil {
+					stale = append(stale, staleSession{session: sessions.Session{GameURL: gameURL, Username: username}, reason: "invalid session file"})
+					continue
+				}
+				candidates = append(candidates, session)
+			}
+			if len(candidates) == 0 {
+				continue
+			}
+
+			api, err := server.NewAPI(gameURL)
+			for _, session := range candidates {
+				var status sessionStatus
+				if err != nil {
+					status = sessionUnreachable
+				} else {
+					status = checkSession(api, session)
+				}
+				switch status {
+				case sessionGameNotFound:
+					stale = append(stale, staleSession{session: session, reason: "game no longer exists"})
+				case sessionPlayerNotFound:
+					stale = append(stale, staleSession{session: session, reason: "player no longer exists"})
+				case sessionUnreachable:
+					if unreachableToo {
+						stale = append(stale, staleSession{session: session, reason: "game server unreachable"})
+					}
+				}
+			}
+		}
+		cli.FinishLoading()
+
+		if len(stale) == 0 {
+			cli.Success("No stale sessions found.")
+			return
+		}
+
+		cli.PrintColor(cli.Cyan, "Stale sessions:")
+		for _, s := range stale {
+			cli.Print("  - %s@%s (%s)", s.session.Username, s.session.GameURL, s.reason)
+		}
+
+		if dryRun {
+			return
+		}
+
+		if !yes {
+			if !isInteractive() {
+				abort(errors.New("refusing to remove sessions without confirmation (use --yes)"))
+			}
+			yes, err = cli.YesNo(fmt.Sprintf("Remove %d sessions?", len(stale)), false)
+			abort(err)
+			if !yes {
+				cli.Print("Canceled.")
+				return
+			}
+		}
+
+		failed := false
+		for _, s := range stale {
+			err = s.session.Remove()
+			if err != nil {
+				cli.Error("Failed to remove %s@%s: %s", s.session.Username, s.session.GameURL, err)
+				failed = true
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+		cli.Success("Successfully removed %d sessions.", len(stale))
+	},
+}
+
+type sessionStatus int
+
+const (
+	sessionValid sessionStatus = iota
+	sessionGameNotFound
+	sessionPlayerNotFound
+	sessionUnreachable
+)
+
+// checkSession asks the game server whether the game and player of session still exist.
+func checkSession(api *server.API, session sessions.Session) sessionStatus {
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(api.BaseURL() + "/games/" + session.GameId + "/players")
+	if err != nil {
+		return sessionUnreachable
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return sessionGameNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return sessionUnreachable
+	}
+
+	type response struct {
+		Players map[string]string `json:"players"`
+	}
+	var data response
+	err = json.NewDecoder(resp.Body).Decode(&data)
+	if err != nil {
+		return sessionUnreachable
+	}
+	if _, ok := data.Players[session.PlayerId]; !ok {
+		return sessionPlayerNotFound
+	}
+	return sessionValid
+}
+
+// parseAge parses a duration like time.ParseDuration but additionally supports days (d) and weeks (w).
+func parseAge(age string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if strings.HasSuffix(age, suffix) {
+			n, err := strconv.Atoi(strings.TrimSuffix(age, suffix))
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid age: %s", age)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+	duration, err := time.ParseDuration(age)
+	if err != nil {
+		return 0, fmt.Errorf("invalid age: %s", age)
+	}
+	return duration, nil
+}
+
+func init() {
+	sessionCmd.AddCommand(sessionPruneCmd)
+	sessionPruneCmd.Flags().BoolP("dry-run", "n", false, "Only list stale sessions without removing them.")
+	sessionPruneCmd.Flags().BoolP("unreachable-too", "", false, "Also remove sessions whose game server cannot be reached.")
+	sessionPruneCmd.Flags().StringP("older-than", "", "", "Only consider sessions which have not been modified for the specified time. (e.g. 30d, 2w, 12h)")
+	sessionPruneCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation.")
+}
@@ -1,10 +1,10 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/Bananenpro/cli"
-	"github.com/code-game-project/go-utils/sessions"
 	"github.com/spf13/cobra"
 )
 
@@ -12,62 +12,67 @@ import (
 var sessionRemoveCmd = &cobra.Command{
 	Use:   "remove",
 	Short: "Remove a session.",
-	Args:  cobra.RangeArgs(0, 2),
+	Long: `Remove a session specified by a game URL and a username, 'username@url' or a pattern like '*@game.example.com'.
+With --all every session matching the patterns (or all sessions if none are specified) and --game is removed.`,
+	Args:              cobra.ArbitraryArgs,
+	ValidArgsFunction: completeSession,
 	Run: func(cmd *cobra.Command, args []string) {
-		var gameURL string
-		var username string
-		if len(args) > 0 {
-			gameURL = args[0]
-			if len(args) > 1 {
-				username = args[1]
-			}
+		all, err := cmd.Flags().GetBool("all")
+		abort(err)
+		gameURL, err := cmd.Flags().GetString("game")
+		abort(err)
+		yes, err := cmd.Flags().GetBool("yes")
+		abort(err)
+
+		if !all && len(args) > 2 {
+			abort(errors.New("multiple patterns are only supported with --all"))
 		}
 
-		if gameURL == "" {
-			urls, err := sessions.ListGames()
-			if err != nil {
-				gameURL, err = cli.Input("Game URL:")
-			} else {
-				var index int
-				index, err = cli.Select("Game URL:", urls)
-				gameURL = urls[index]
+		selected, err := selectSessions(args, all, gameURL)
+		abortf("Failed to select session: %s", err)
+
+		if !yes {
+			if !isInteractive() {
+				abort(errors.New("refusing to remove sessions without confirmation (use --yes)"))
 			}
-			if err != nil {
+			question := fmt.Sprintf("Are you sure you want to remove %s@%s?", selected[0].Username, selected[0].GameURL)
+			if len(selected) > 1 {
+				for _, s := range selected {
+					cli.Print("%s@%s", s.Username, s.GameURL)
+				}
+				question = fmt.Sprintf("Are you sure you want to remove %d sessions?", len(selected))
+			}
+			yes, err = cli.YesNo(question, false)
+			abort(err)
+			if !yes {
+				cli.Error("Canceled.")
 				return
 			}
 		}
 
-		if username == "" {
-			usernames, err := sessions.ListUsernames(gameURL)
+		failed := false
+		for _, s := range selected {
+			err = s.Remove()
 			if err != nil {
-				username, err = cli.Input("Username:")
-			} else {
-				var index int
-				index, err = cli.Select("Username:", usernames)
-				username = usernames[index]
-			}
-			if err != nil {
-				return
+				cli.Error("Failed to remove session %s@%s: %s", s.Username, s.GameURL, err)
+				failed = true
 			}
 		}
-
-		yes, err := cli.YesNo(fmt.Sprintf("Are you sure you want to remove %s@%s?", username, gameURL), false)
-		if !yes {
-			if err == nil {
-				cli.Error("Canceled.")
-			}
-			return
+		if failed {
+			abort(errors.New("failed to remove some sessions"))
 		}
 
-		err = sessions.Session{
-			GameURL:  gameURL,
-			Username: username,
-		}.Remove()
-		abortf("Failed to remove session: %s", err)
-		cli.Success("Successfully removed session.")
+		if len(selected) == 1 {
+			cli.Success("Successfully removed session.")
+		} else {
+			cli.Success("Successfully removed %d sessions.", len(selected))
+		}
 	},
 }
 
 func init() {
 	sessionCmd.AddCommand(sessionRemoveCmd)
+	sessionRemoveCmd.Flags().BoolP("all", "a", false, "Remove all sessions matching the patterns and --game.")
+	sessionRemoveCmd.Flags().StringP("game", "g", "", "Only remove sessions of this game URL (may be a pattern).")
+	sessionRemoveCmd.Flags().BoolP("yes", "y", false, "Do not ask for confirmation.")
 }
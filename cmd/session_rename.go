@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/Bananenpro/cli"
+	"github.com/code-game-project/go-utils/external"
+	"github.com/spf13/cobra"
+)
+
+// sessionRenameCmd represents the session rename command
+var sessionRenameCmd = &cobra.Command{
+	Use:   "rename",
+	Short: "Change the username or game URL under which a session is stored.",
+	Long: `Change the username or game URL under which a session is stored.
+
+The session is specified either by its game URL and username or by a single 'username@url',
+optionally followed by the new username:
+
+  codegame session rename <url> <username> <new_username>
+  codegame session rename <username>@<url> <new_username>`,
+	Args:              cobra.RangeArgs(0, 3),
+	ValidArgsFunction: completeSession,
+	Run: func(cmd *cobra.Command, args []string) {
+		force, err := cmd.Flags().GetBool("force")
+		abort(err)
+		newURL, err := cmd.Flags().GetString("url")
+		abort(err)
+
+		args, newUsername := splitRenameArgs(args)
+
+		session, err := selectSession(args)
+		abortf("Failed to load session: %s", err)
+
+		if newUsername == "" && isInteractive() {
+			newUsername, err = cli.InputOptional("New username (leave empty to keep):")
+			abort(err)
+		}
+		if newUsername == "" {
+			newUsername = session.Username
+		}
+		if newURL == "" {
+			newURL = session.GameURL
+		}
+		newURL = external.TrimURL(newURL)
+
+		if newUsername == session.Username && newURL == session.GameURL {
+			abort(errors.New("nothing to rename"))
+		}
+
+		renamed := session
+		renamed.GameURL = newURL
+		renamed.Username = newUsername
+		err = replaceSession(session, renamed, force)
+		abortf("Failed to rename session: %s", err)
+
+		cli.Success("Successfully renamed %s@%s to %s@%s.", session.Username, session.GameURL, renamed.Username, renamed.GameURL)
+	},
+}
+
+// splitRenameArgs splits the arguments of 'session rename' into the arguments selecting the session and the new username.
+// The new username follows either a game URL and a username or a single 'username@url'.
+func splitRenameArgs(args []string) (selectArgs []string, newUsername string) {
+	switch {
+	case len(args) > 2:
+		return args[:2], args[2]
+	case len(args) == 2 && strings.Contains(args[0], "@"):
+		return args[:1], args[1]
+	default:
+		return args, ""
+	}
+}
+
+func init() {
+	sessionCmd.AddCommand(sessionRenameCmd)
+	sessionRenameCmd.Flags().StringP("url", "", "", "Move the session to a different game URL.")
+	sessionRenameCmd.Flags().BoolP("force", "f", false, "Overwrite an existing session with the new name.")
+}
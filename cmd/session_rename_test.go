@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitRenameArgs(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            []string
+		wantSelectArgs  []string
+		wantNewUsername string
+	}{
+		{"no args", []string{}, []string{}, ""},
+		{"url", []string{"example.com"}, []string{"example.com"}, ""},
+		{"username@url", []string{"alice@example.com"}, []string{"alice@example.com"}, ""},
+		{"url and username", []string{"example.com", "alice"}, []string{"example.com", "alice"}, ""},
+		{"url, username and new username", []string{"example.com", "alice", "bob"}, []string{"example.com", "alice"}, "bob"},
+		{"username@url and new username", []string{"alice@example.com", "bob"}, []string{"alice@example.com"}, "bob"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			selectArgs, newUsername := splitRenameArgs(test.args)
+			if !reflect.DeepEqual(selectArgs, test.wantSelectArgs) || newUsername != test.wantNewUsername {
+				t.Errorf("splitRenameArgs(%q) = %q, %q, want %q, %q", test.args, selectArgs, newUsername, test.wantSelectArgs, test.wantNewUsername)
+			}
+		})
+	}
+}
@@ -1,28 +1,51 @@
 package cmd
 
 import (
+	"strings"
+
 	"github.com/mattn/go-colorable"
 	"github.com/spf13/cobra"
 )
 
 // sessionShowCmd represents the session show command
 var sessionShowCmd = &cobra.Command{
-	Use:   "show",
-	Short: "Show the session data.",
-	Args:  cobra.RangeArgs(0, 2),
+	Use:               "show",
+	Short:             "Show the session data.",
+	Args:              cobra.RangeArgs(0, 2),
+	ValidArgsFunction: completeSession,
 	Run: func(cmd *cobra.Command, args []string) {
-		session, err := selectSession(args)
-		abortf("Failed to load session: %s", err)
+		reveal, err := cmd.Flags().GetBool("reveal")
+		abort(err)
+
+		session, ok := findActiveSession()
+		if !ok || len(args) > 0 {
+			session, err = selectSession(args)
+			abortf("Failed to load session: %s", err)
+		}
+
+		secret := session.PlayerSecret
+		if !reveal {
+			secret = maskSecret(secret)
+		}
 
 		out := colorable.NewColorableStdout()
 		printInfoProperty(out, "Game URL", session.GameURL, 14)
 		printInfoProperty(out, "Username", session.Username, 14)
 		printInfoProperty(out, "Game ID", session.GameId, 14)
 		printInfoProperty(out, "Player ID", session.PlayerId, 14)
-		printInfoProperty(out, "Player Secret", session.PlayerSecret, 14)
+		printInfoProperty(out, "Player Secret", secret, 14)
 	},
 }
 
+// maskSecret replaces all but the last 4 characters of secret with '*'.
+func maskSecret(secret string) string {
+	if len(secret) <= 8 {
+		return strings.Repeat("*", len(secret))
+	}
+	return strings.Repeat("*", len(secret)-4) + secret[len(secret)-4:]
+}
+
 func init() {
 	sessionCmd.AddCommand(sessionShowCmd)
+	sessionShowCmd.Flags().BoolP("reveal", "r", false, "Show the player secret in plain text.")
 }
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+
+	"github.com/Bananenpro/cli"
+	"github.com/spf13/cobra"
+)
+
+// sessionUnlockCmd represents the session unlock command
+var sessionUnlockCmd = &cobra.Command{
+	Use:   "unlock",
+	Short: "Decrypt all sessions encrypted with 'codegame session lock'.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !sessionStoreLocked() {
+			abort(errors.New("the session store is not locked"))
+		}
+
+		vault, err := loadVault()
+		abortf("Failed to load session vault: %s", err)
+		key, err := vaultKey(vault)
+		abort(err)
+		list, err := vault.openSessions(key)
+		abort(err)
+
+		for _, s := range list {
+			if sessionExists(s.GameURL, s.Username) {
+				cli.Warn("Keeping existing unencrypted session %s@%s.", s.Username, s.GameURL)
+				continue
+			}
+			// saveSession would add the session back to the vault, which is only removed afterwards.
+			err = writeSessionFile(s.session())
+			abortf("Failed to save session: %s", err)
+		}
+
+		err = os.Remove(sessionVaultPath)
+		abortf("Failed to remove session vault: %s", err)
+
+		cli.Success("Successfully unlocked %d sessions.", len(list))
+	},
+}
+
+func init() {
+	sessionCmd.AddCommand(sessionUnlockCmd)
+}
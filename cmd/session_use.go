@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/Bananenpro/cli"
+	"github.com/adrg/xdg"
+	"github.com/code-game-project/go-utils/cgfile"
+	"github.com/code-game-project/go-utils/external"
+	"github.com/code-game-project/go-utils/sessions"
+	"github.com/spf13/cobra"
+)
+
+// sessionUseCmd represents the session use command
+var sessionUseCmd = &cobra.Command{
+	Use:   "use",
+	Short: "Set the active session of the current project or game URL.",
+	Long: `Set the active session of the current project or, with --global or outside of a project, of the game URL.
+
+The active session is used by 'codegame run', which exposes it to the application with the
+CG_GAME_ID, CG_PLAYER_ID and CG_PLAYER_SECRET environment variables, and as the default of
+'codegame session show' and 'codegame share spectate'.
+
+Outside of a project the global active session of the game URL in CG_GAME_URL is used or,
+if CG_GAME_URL is not set and there is only one global active session, that session.`,
+	Args:              cobra.RangeArgs(0, 2),
+	ValidArgsFunction: completeSession,
+	Run: func(cmd *cobra.Command, args []string) {
+		global, err := cmd.Flags().GetBool("global")
+		abort(err)
+		unset, err := cmd.Flags().GetBool("unset")
+		abort(err)
+
+		active, err := loadActiveSessions()
+		abortf("Failed to load active sessions: %s", err)
+
+		projectRoot, err := cgfile.FindProjectRoot()
+		if err != nil {
+			global = true
+		}
+
+		if unset {
+			if global {
+				var gameURL string
+				if len(args) > 0 {
+					gameURL = args[0]
+				} else if gameURL = findGameURL(); gameURL == "" {
+					gameURL, err = cli.Input("Game URL:")
+					abort(err)
+				}
+				delete(active.Games, gameURL)
+			} else {
+				delete(active.Projects, projectRoot)
+			}
+			abortf("Failed to save active sessions: %s", active.save())
+			cli.Success("Successfully unset the active session.")
+			return
+		}
+
+		if len(args) == 0 && !global {
+			if gameURL := findGameURL(); gameURL != "" {
+				args = []string{gameURL}
+			}
+		}
+		session, err := selectSession(args)
+		abortf("Failed to load session: %s", err)
+
+		if global {
+			active.Games[session.GameURL] = session.Username
+		} else {
+			active.Projects[projectRoot] = activeSession{
+				GameURL:  session.GameURL,
+				Username: session.Username,
+			}
+		}
+		abortf("Failed to save active sessions: %s", active.save())
+
+		if global {
+			cli.Success("%s@%s is now the active session for %s.", session.Username, session.GameURL, session.GameURL)
+		} else {
+			cli.Success("%s@%s is now the active session of the project in '%s'.", session.Username, session.GameURL, projectRoot)
+		}
+	},
+}
+
+var activeSessionsPath = filepath.Join(xdg.DataHome, "codegame", "active_sessions.json")
+
+type activeSession struct {
+	GameURL  string `json:"game_url"`
+	Username string `json:"username"`
+}
+
+type activeSessions struct {
+	// Projects maps project root directories to their active session.
+	Projects map[string]activeSession `json:"projects"`
+	// Games maps game URLs to the username of their active session.
+	Games map[string]string `json:"games"`
+}
+
+func loadActiveSessions() (activeSessions, error) {
+	active := activeSessions{
+		Projects: make(map[string]activeSession),
+		Games:    make(map[string]string),
+	}
+	data, err := os.ReadFile(activeSessionsPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return active, nil
+		}
+		return active, err
+	}
+	err = json.Unmarshal(data, &active)
+	if active.Projects == nil {
+		active.Projects = make(map[string]activeSession)
+	}
+	if active.Games == nil {
+		active.Games = make(map[string]string)
+	}
+	return active, err
+}
+
+func (a activeSessions) save() error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(activeSessionsPath), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(activeSessionsPath, data, 0o644)
+}
+
+// findActiveSession returns the active session of the current project or, if the project has none, the active session of its game URL.
+// Outside of a project the active session of the game URL in CG_GAME_URL is used or, if it is not set, the only global active session.
+func findActiveSession() (sessions.Session, bool) {
+	active, err := loadActiveSessions()
+	if err != nil {
+		return sessions.Session{}, false
+	}
+
+	var gameURL string
+	if projectRoot, err := cgfile.FindProjectRoot(); err == nil {
+		if s, ok := active.Projects[projectRoot]; ok {
+			session, err := sessions.LoadSession(s.GameURL, s.Username)
+			return session, err == nil
+		}
+		gameURL = findGameURL()
+	} else if gameURL = external.TrimURL(os.Getenv("CG_GAME_URL")); gameURL == "" && len(active.Games) == 1 {
+		for url := range active.Games {
+			gameURL = url
+		}
+	}
+
+	if username, ok := active.Games[gameURL]; ok && gameURL != "" {
+		session, err := sessions.LoadSession(gameURL, username)
+		return session, err == nil
+	}
+	return sessions.Session{}, false
+}
+
+func init() {
+	sessionCmd.AddCommand(sessionUseCmd)
+	sessionUseCmd.Flags().BoolP("global", "g", false, "Set the active session for the game URL instead of the current project.")
+	sessionUseCmd.Flags().BoolP("unset", "", false, "Remove the active session.")
+}
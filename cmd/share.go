@@ -1,7 +1,21 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Bananenpro/cli"
+	"github.com/adrg/xdg"
+	"github.com/code-game-project/codegame-cli/share"
 	"github.com/code-game-project/go-utils/cgfile"
+	"github.com/code-game-project/go-utils/config"
+	"github.com/mattn/go-colorable"
+	"github.com/skip2/go-qrcode"
 	"github.com/spf13/cobra"
 )
 
@@ -25,6 +39,148 @@ func findGameURL() string {
 	return config.URL
 }
 
+// newShareClient returns a client for the Share server at shareURL or, if it is empty, for the configured Share server.
+// The request timeout and the proxy can be set with the CG_SHARE_TIMEOUT and CG_SHARE_PROXY environment variables.
+func newShareClient(shareURL string) *share.Client {
+	if shareURL == "" {
+		shareURL = config.Load().ShareURL
+	}
+
+	var options share.Options
+	if timeout := os.Getenv("CG_SHARE_TIMEOUT"); timeout != "" {
+		var err error
+		options.Timeout, err = time.ParseDuration(timeout)
+		abortf("Invalid CG_SHARE_TIMEOUT: %s", err)
+	}
+	options.Proxy = os.Getenv("CG_SHARE_PROXY")
+
+	client, err := share.NewClient(shareURL, options)
+	abort(err)
+	return client
+}
+
+var shareHistoryPath = filepath.Join(xdg.DataHome, "codegame", "share_history.json")
+
+// shareEntry is a share created with CodeGame Share.
+type shareEntry struct {
+	Id       string `json:"id"`
+	Type     string `json:"type"`
+	ShareURL string `json:"share_url"`
+	GameURL  string `json:"game_url"`
+	GameId   string `json:"game_id,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password bool   `json:"password"`
+	// Token is the owner token returned by the Share server, which is required to revoke the share.
+	Token   string    `json:"token,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// target returns a short description of the shared game or session.
+func (s shareEntry) target() string {
+	if s.Username != "" {
+		return s.Username + "@" + s.GameURL
+	}
+	return fmt.Sprintf("%s (%s)", s.GameURL, s.GameId)
+}
+
+func loadShareHistory() ([]shareEntry, error) {
+	data, err := os.ReadFile(shareHistoryPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var history []shareEntry
+	err = json.Unmarshal(data, &history)
+	return history, err
+}
+
+func saveShareHistory(history []shareEntry) error {
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(shareHistoryPath), 0o755)
+	if err != nil {
+		return err
+	}
+	// The history contains the owner tokens of the shares.
+	return os.WriteFile(shareHistoryPath, data, 0o600)
+}
+
+// recordShare adds entry to the share history. Failures are only reported as a warning because the share was already created.
+func recordShare(entry shareEntry) {
+	entry.Created = time.Now()
+	history, err := loadShareHistory()
+	if err == nil {
+		err = saveShareHistory(append(history, entry))
+	}
+	if err != nil {
+		cli.Warn("Failed to save share history: %s", err)
+	}
+}
+
+// findShare returns the index of the share with id in history or -1 if it does not exist.
+func findShare(history []shareEntry, id string) int {
+	for i, s := range history {
+		if s.Id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// printShareQR renders link as a QR code in the terminal and/or writes it to a PNG file depending on the --qr and --qr-png flags of cmd.
+func printShareQR(cmd *cobra.Command, link string) {
+	printQR, err := cmd.Flags().GetBool("qr")
+	abort(err)
+	pngFile, err := cmd.Flags().GetString("qr-png")
+	abort(err)
+	if !printQR && pngFile == "" {
+		return
+	}
+
+	code, err := qrcode.New(link, qrcode.Medium)
+	abortf("Failed to generate QR code: %s", err)
+
+	if printQR {
+		printQRCode(code)
+	}
+	if pngFile != "" {
+		err = code.WriteFile(512, pngFile)
+		abortf("Failed to write QR code: %s", err)
+		cli.Success("Successfully wrote the QR code to %s.", pngFile)
+	}
+}
+
+// printQRCode prints code with Unicode half blocks, which represent two rows of modules per line.
+// The colors are set explicitly to white on black, so that the code can be scanned regardless of the terminal theme.
+func printQRCode(code *qrcode.QRCode) {
+	bitmap := code.Bitmap()
+	out := colorable.NewColorableStdout()
+	for y := 0; y < len(bitmap); y += 2 {
+		var line strings.Builder
+		line.WriteString("\x1b[97;40m")
+		for x := range bitmap[y] {
+			top := !bitmap[y][x]
+			bottom := y+1 < len(bitmap) && !bitmap[y+1][x]
+			switch {
+			case top && bottom:
+				line.WriteString("█")
+			case top:
+				line.WriteString("▀")
+			case bottom:
+				line.WriteString("▄")
+			default:
+				line.WriteString(" ")
+			}
+		}
+		line.WriteString("\x1b[0m")
+		fmt.Fprintln(out, line.String())
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(shareCmd)
 }
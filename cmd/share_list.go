@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Bananenpro/cli"
+	"github.com/spf13/cobra"
+)
+
+// shareListCmd represents the share list command
+var shareListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all shares created with this device.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		shareType, err := cmd.Flags().GetString("type")
+		abort(err)
+
+		history, err := loadShareHistory()
+		abortf("Failed to load share history: %s", err)
+
+		count := 0
+		for i := len(history) - 1; i >= 0; i-- {
+			s := history[i]
+			if shareType != "" && s.Type != shareType {
+				continue
+			}
+			details := fmt.Sprintf("%s, %s", s.Type, s.Created.Local().Format("2006-01-02 15:04"))
+			if s.Password {
+				details += ", password protected"
+			}
+			cli.PrintColor(cli.CyanBold, s.Id)
+			cli.Print("  %s (%s)", s.target(), details)
+			count++
+		}
+		if count == 0 {
+			cli.Print("No shares found.")
+		}
+	},
+}
+
+func init() {
+	shareCmd.AddCommand(shareListCmd)
+	shareListCmd.Flags().StringP("type", "t", "", "Only list shares of this type. (possible values: game, spectate, session)")
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Bananenpro/cli"
+	"github.com/code-game-project/codegame-cli/share"
+	"github.com/spf13/cobra"
+)
+
+// shareRevokeCmd represents the share revoke command
+var shareRevokeCmd = &cobra.Command{
+	Use:   "revoke <id>",
+	Short: "Delete a share from CodeGame Share.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		forget, err := cmd.Flags().GetBool("forget")
+		abort(err)
+		token, err := cmd.Flags().GetString("token")
+		abort(err)
+
+		entry, known := lookupShare(cmd, args[0])
+		if forget && !known {
+			abort(fmt.Errorf("share '%s' is not in the share history", entry.Id))
+		}
+		if token == "" {
+			token = entry.Token
+		}
+
+		if !forget {
+			client := newShareClient(entry.ShareURL)
+			err = client.Delete(entry.Type, entry.Id, token)
+			switch {
+			case err == nil:
+				cli.Success("Successfully revoked share '%s'.", entry.Id)
+			case errors.Is(err, share.ErrNotFound):
+				cli.Warn("Share '%s' does not exist anymore.", entry.Id)
+			case errors.Is(err, share.ErrForbidden) && token == "":
+				abort(fmt.Errorf("no owner token is known for share '%s' (use --token to specify it)", entry.Id))
+			case errors.Is(err, share.ErrForbidden):
+				abort(fmt.Errorf("wrong owner token for share '%s'", entry.Id))
+			case errors.Is(err, share.ErrNotSupported):
+				abort(fmt.Errorf("%s does not support revoking shares (use --forget to only remove it from the share history)", client.BaseURL()))
+			default:
+				abortf("Failed to revoke share: %s", err)
+			}
+		}
+
+		if known {
+			history, err := loadShareHistory()
+			abortf("Failed to load share history: %s", err)
+			if index := findShare(history, entry.Id); index >= 0 {
+				history = append(history[:index], history[index+1:]...)
+				abortf("Failed to save share history: %s", saveShareHistory(history))
+			}
+			if forget {
+				cli.Success("Removed share '%s' from the share history.", entry.Id)
+			}
+		}
+	},
+}
+
+func init() {
+	shareCmd.AddCommand(shareRevokeCmd)
+	shareRevokeCmd.Flags().StringP("type", "t", "", "The type of a share which is not in the share history. (possible values: game, spectate, session)")
+	shareRevokeCmd.Flags().StringP("token", "", "", "The owner token of the share. (default: the token in the share history)")
+	shareRevokeCmd.Flags().BoolP("forget", "", false, "Only remove the share from the local share history.")
+}
@@ -0,0 +1,458 @@
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Bananenpro/cli"
+	"github.com/adrg/xdg"
+	"github.com/code-game-project/codegame-cli/share"
+	"github.com/spf13/cobra"
+)
+
+// shareServeCmd represents the share serve command
+var shareServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run a CodeGame Share server.",
+	Long: `Run a CodeGame Share server, which stores its shares in a directory.
+
+Every created share gets a random owner token, which is returned next to its id and is required to delete it.
+
+Point the share_url of the CodeGame config file to the server to use it with the share and session import/export commands.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		addr, err := cmd.Flags().GetString("addr")
+		abort(err)
+		port, err := cmd.Flags().GetInt("port")
+		abort(err)
+		dir, err := cmd.Flags().GetString("data")
+		abort(err)
+		rateLimit, err := cmd.Flags().GetInt("rate-limit")
+		abort(err)
+		maxAge, err := cmd.Flags().GetDuration("max-age")
+		abort(err)
+
+		err = os.MkdirAll(dir, 0o700)
+		abortf("Failed to create data directory: %s", err)
+
+		s := &shareServer{
+			dir:       dir,
+			maxAge:    maxAge,
+			requests:  newRateLimiter(rateLimit, time.Minute),
+			passwords: newRateLimiter(5, time.Minute),
+		}
+
+		listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", addr, port))
+		abortf("Failed to start share server: %s", err)
+		httpServer := &http.Server{
+			Handler:           s.handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt)
+		go func() {
+			<-interrupt
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			httpServer.Shutdown(ctx)
+		}()
+
+		cli.Success("CodeGame Share is listening on %s.", listener.Addr())
+		cli.Print("Shares are stored in '%s'.", dir)
+		cli.Print("Set \"share_url\" to \"http://<host>:%d\" in %s to use this server.", listener.Addr().(*net.TCPAddr).Port, filepath.Join(xdg.ConfigHome, "codegame", "config.json"))
+
+		err = httpServer.Serve(listener)
+		if !errors.Is(err, http.ErrServerClosed) {
+			abort(err)
+		}
+	},
+}
+
+// storedShare is the representation of a share in the data directory of 'share serve'.
+type storedShare struct {
+	Type    string    `json:"type"`
+	Created time.Time `json:"created"`
+	Salt    []byte    `json:"salt,omitempty"`
+	Hash    []byte    `json:"hash,omitempty"`
+	// OwnerHash is the SHA-256 hash of the owner token, which is required to delete the share.
+	OwnerHash []byte          `json:"owner_hash,omitempty"`
+	Data      json.RawMessage `json:"data"`
+}
+
+type shareServer struct {
+	dir       string
+	maxAge    time.Duration
+	requests  *rateLimiter
+	passwords *rateLimiter
+
+	lock sync.Mutex
+}
+
+func (s *shareServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		if wait, ok := s.requests.allow(clientIP(req)); !ok {
+			writeRateLimited(w, wait)
+			return
+		}
+
+		path := strings.Trim(req.URL.Path, "/")
+		switch {
+		case path == "" && req.Method == http.MethodGet:
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("CodeGame Share\n"))
+		case (path == share.TypeGame || path == share.TypeSpectate || path == share.TypeSession) && req.Method == http.MethodPost:
+			s.handleCreate(w, req, path)
+		case path != "" && !strings.Contains(path, "/") && req.Method == http.MethodGet:
+			s.handleGet(w, req, path)
+		case path != "" && !strings.Contains(path, "/") && req.Method == http.MethodDelete:
+			s.handleDelete(w, req, path)
+		case path == share.TypeGame || path == share.TypeSpectate || path == share.TypeSession:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		default:
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "not found"})
+		}
+	})
+	return mux
+}
+
+func (s *shareServer) handleCreate(w http.ResponseWriter, req *http.Request, shareType string) {
+	var data map[string]json.RawMessage
+	err := json.NewDecoder(http.MaxBytesReader(w, req.Body, 64*1024)).Decode(&data)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	required := []string{"game_url", "game_id"}
+	switch shareType {
+	case share.TypeSpectate:
+		required = append(required, "player_id", "player_secret")
+	case share.TypeSession:
+		required = []string{"game_url", "username", "session"}
+	}
+	for _, field := range required {
+		if len(data[field]) == 0 || string(data[field]) == `""` || string(data[field]) == "null" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("missing field: %s", field)})
+			return
+		}
+	}
+
+	stored := storedShare{
+		Type:    shareType,
+		Created: time.Now(),
+	}
+	if shareType == share.TypeSession {
+		var password string
+		json.Unmarshal(data["password"], &password)
+		delete(data, "password")
+		if password != "" {
+			stored.Salt = make([]byte, 16)
+			_, err = rand.Read(stored.Salt)
+			if err == nil {
+				stored.Hash, err = deriveKey(password, stored.Salt)
+			}
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+				return
+			}
+		}
+	}
+	stored.Data, err = json.Marshal(data)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+
+	token, err := newOwnerToken()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+		return
+	}
+	ownerHash := sha256.Sum256([]byte(token))
+	stored.OwnerHash = ownerHash[:]
+
+	id, err := s.save(stored)
+	if err != nil {
+		cli.Error("Failed to save share: %s", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+		return
+	}
+	writeJSON(w, http.StatusCreated, map[string]string{"id": id, "token": token})
+}
+
+func (s *shareServer) handleGet(w http.ResponseWriter, req *http.Request, id string) {
+	stored, ok := s.lookup(w, req, id)
+	if !ok || !s.checkPassword(w, req, stored) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(stored.Data)
+}
+
+func (s *shareServer) handleDelete(w http.ResponseWriter, req *http.Request, id string) {
+	stored, ok := s.lookup(w, req, id)
+	if !ok || !s.checkOwnerToken(w, req, stored) {
+		return
+	}
+	s.lock.Lock()
+	err := os.Remove(s.path(id))
+	s.lock.Unlock()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal server error"})
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// lookup loads the share with id and verifies the type query parameter.
+// If the share does not exist, an error response is written and false is returned.
+func (s *shareServer) lookup(w http.ResponseWriter, req *http.Request, id string) (storedShare, bool) {
+	stored, err := s.load(id)
+	shareType := req.URL.Query().Get("type")
+	if err != nil || (shareType != "" && shareType != stored.Type) {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "share not found"})
+		return stored, false
+	}
+	return stored, true
+}
+
+// checkPassword verifies the Password header of a request for a password protected share.
+// If the password is missing or wrong, an error response is written and false is returned.
+func (s *shareServer) checkPassword(w http.ResponseWriter, req *http.Request, stored storedShare) bool {
+	if len(stored.Hash) == 0 {
+		return true
+	}
+	ip := clientIP(req)
+	if wait, ok := s.passwords.blocked(ip); ok {
+		writeRateLimited(w, wait)
+		return false
+	}
+	password := req.Header.Get("Password")
+	if password == "" {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "password required"})
+		return false
+	}
+	hash, err := deriveKey(password, stored.Salt)
+	if err != nil || subtle.ConstantTimeCompare(hash, stored.Hash) != 1 {
+		s.passwords.allow(ip)
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "wrong password"})
+		return false
+	}
+	return true
+}
+
+// checkOwnerToken verifies the Owner-Token header of a request, which modifies a share.
+// Shares without an owner token cannot be modified. If the token is missing or wrong, an error response is written and false is returned.
+func (s *shareServer) checkOwnerToken(w http.ResponseWriter, req *http.Request, stored storedShare) bool {
+	ip := clientIP(req)
+	if wait, ok := s.passwords.blocked(ip); ok {
+		writeRateLimited(w, wait)
+		return false
+	}
+	token := req.Header.Get("Owner-Token")
+	if token == "" {
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "owner token required"})
+		return false
+	}
+	hash := sha256.Sum256([]byte(token))
+	if len(stored.OwnerHash) == 0 || subtle.ConstantTimeCompare(hash[:], stored.OwnerHash) != 1 {
+		s.passwords.allow(ip)
+		writeJSON(w, http.StatusForbidden, map[string]string{"error": "wrong owner token"})
+		return false
+	}
+	return true
+}
+
+func (s *shareServer) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// load reads the share with id from the data directory. Expired shares are removed.
+func (s *shareServer) load(id string) (storedShare, error) {
+	var stored storedShare
+	for _, c := range id {
+		if !strings.ContainsRune(shareIdAlphabet, c) {
+			return stored, os.ErrNotExist
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	data, err := os.ReadFile(s.path(id))
+	if err != nil {
+		return stored, err
+	}
+	err = json.Unmarshal(data, &stored)
+	if err != nil {
+		return stored, err
+	}
+	if s.maxAge > 0 && time.Since(stored.Created) > s.maxAge {
+		os.Remove(s.path(id))
+		return stored, os.ErrNotExist
+	}
+	return stored, nil
+}
+
+// save writes stored to a new file in the data directory and returns its id.
+func (s *shareServer) save(stored storedShare) (string, error) {
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return "", err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for {
+		id, err := newShareId()
+		if err != nil {
+			return "", err
+		}
+		file, err := os.OpenFile(s.path(id), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		_, err = file.Write(data)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(s.path(id))
+			return "", err
+		}
+		return id, nil
+	}
+}
+
+const shareIdAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+func newShareId() (string, error) {
+	id := make([]byte, 8)
+	for i := range id {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(shareIdAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		id[i] = shareIdAlphabet[n.Int64()]
+	}
+	return string(id), nil
+}
+
+// newOwnerToken returns a random token, which authorizes the deletion of a share.
+func newOwnerToken() (string, error) {
+	token := make([]byte, 32)
+	_, err := rand.Read(token)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// rateLimiter allows a limited number of events per client in fixed time windows.
+type rateLimiter struct {
+	limit  int
+	window time.Duration
+
+	lock    sync.Mutex
+	clients map[string]*rateWindow
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:   limit,
+		window:  window,
+		clients: make(map[string]*rateWindow),
+	}
+}
+
+// allow records an event of client and reports whether it is within the limit.
+// If it is not, the time until the next event is allowed is returned.
+func (r *rateLimiter) allow(client string) (time.Duration, bool) {
+	if r.limit <= 0 {
+		return 0, true
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	w := r.current(client)
+	w.count++
+	if w.count > r.limit {
+		return time.Until(w.start.Add(r.window)), false
+	}
+	return 0, true
+}
+
+// blocked reports whether client has exceeded the limit without recording an event.
+func (r *rateLimiter) blocked(client string) (time.Duration, bool) {
+	if r.limit <= 0 {
+		return 0, false
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	w := r.current(client)
+	if w.count >= r.limit {
+		return time.Until(w.start.Add(r.window)), true
+	}
+	return 0, false
+}
+
+func (r *rateLimiter) current(client string) *rateWindow {
+	now := time.Now()
+	w, ok := r.clients[client]
+	if !ok || now.Sub(w.start) >= r.window {
+		for c, old := range r.clients {
+			if now.Sub(old.start) >= r.window {
+				delete(r.clients, c)
+			}
+		}
+		w = &rateWindow{start: now}
+		r.clients[client] = w
+	}
+	return w
+}
+
+func writeRateLimited(w http.ResponseWriter, wait time.Duration) {
+	w.Header().Set("Retry-After", fmt.Sprintf("%d", int(wait.Seconds())+1))
+	writeJSON(w, http.StatusTooManyRequests, map[string]string{"error": "too many requests"})
+}
+
+func clientIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+func init() {
+	shareCmd.AddCommand(shareServeCmd)
+	shareServeCmd.Flags().StringP("addr", "", "", "The address to listen on. (default: all interfaces)")
+	shareServeCmd.Flags().IntP("port", "p", 8080, "The port to listen on.")
+	shareServeCmd.Flags().StringP("data", "d", filepath.Join(xdg.DataHome, "codegame", "share-server"), "The directory in which shares are stored.")
+	shareServeCmd.Flags().IntP("rate-limit", "", 60, "The maximum number of requests per minute per client. 0 disables rate limiting.")
+	shareServeCmd.Flags().DurationP("max-age", "", 0, "Delete shares older than this duration. 0 keeps shares forever.")
+}
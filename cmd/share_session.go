@@ -1,21 +1,17 @@
 package cmd
 
 import (
-	"bytes"
-	"encoding/json"
-	"net/http"
-
 	"github.com/Bananenpro/cli"
-	"github.com/code-game-project/go-utils/config"
-	"github.com/code-game-project/go-utils/external"
+	"github.com/code-game-project/codegame-cli/share"
 	"github.com/spf13/cobra"
 )
 
 // shareSessionCmd represents the share session command
 var shareSessionCmd = &cobra.Command{
-	Use:   "session",
-	Short: "Share a session with CodeGame Share (same as 'codegame session export').",
-	Args:  cobra.RangeArgs(0, 2),
+	Use:               "session",
+	Short:             "Share a session with CodeGame Share (same as 'codegame session export').",
+	Args:              cobra.RangeArgs(0, 2),
+	ValidArgsFunction: completeSession,
 	Run: func(cmd *cobra.Command, args []string) {
 		session, err := selectSession(args)
 		abortf("Failed to load session: %s", err)
@@ -47,43 +43,27 @@ var shareSessionCmd = &cobra.Command{
 			Password: password,
 		}
 
-		jsonData, err := json.Marshal(data)
-		abort(err)
-
-		conf := config.Load()
-		shareURL := external.TrimURL(conf.ShareURL)
-		baseURL := external.BaseURL("http", external.IsTLS(shareURL), shareURL)
-
-		resp, err := http.Post(baseURL+"/session", "application/json", bytes.NewBuffer(jsonData))
-		if err != nil {
-			cli.Error("Failed to upload session: %s", err)
-			return
-		}
-		if resp.StatusCode != http.StatusCreated {
-			type response struct {
-				Error string `json:"error"`
-			}
-			var res response
-			err = json.NewDecoder(resp.Body).Decode(&res)
-			if err != nil {
-				cli.Error("Failed to decode error message: %s", err)
-			} else {
-				cli.Error(res.Error)
-			}
-			return
-		}
-
-		type response struct {
-			Id string `json:"id"`
-		}
-		var res response
-		err = json.NewDecoder(resp.Body).Decode(&res)
-		abortf("Failed to decode server response: %s", err)
+		client := newShareClient("")
+		id, token, err := client.Create(share.TypeSession, data)
+		abortf("Failed to upload session: %s", err)
+		recordShare(shareEntry{
+			Id:       id,
+			Type:     share.TypeSession,
+			Token:    token,
+			ShareURL: client.BaseURL(),
+			GameURL:  session.GameURL,
+			GameId:   session.GameId,
+			Username: session.Username,
+			Password: password != "",
+		})
 		cli.Success("Success! You can import your session on another device with the following command:")
-		cli.PrintColor(cli.Cyan, "codegame session import %s", res.Id)
+		cli.PrintColor(cli.Cyan, "codegame session import %s", id)
+		printShareQR(cmd, client.Link(id))
 	},
 }
 
 func init() {
 	shareCmd.AddCommand(shareSessionCmd)
+	shareSessionCmd.Flags().BoolP("qr", "", false, "Print the link as a QR code.")
+	shareSessionCmd.Flags().StringP("qr-png", "", "", "Write the link as a QR code to this PNG file.")
 }
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Bananenpro/cli"
+	"github.com/code-game-project/codegame-cli/share"
+	"github.com/mattn/go-colorable"
+	"github.com/spf13/cobra"
+)
+
+// shareShowCmd represents the share show command
+var shareShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show the current state of a share on CodeGame Share.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		reveal, err := cmd.Flags().GetBool("reveal")
+		abort(err)
+
+		entry, known := lookupShare(cmd, args[0])
+		client := newShareClient(entry.ShareURL)
+
+		var data map[string]any
+		err = withSharePassword(func(password string) error {
+			return client.Get(entry.Type, entry.Id, password, &data)
+		})
+		if errors.Is(err, share.ErrNotFound) {
+			abort(fmt.Errorf("share '%s' does not exist anymore", entry.Id))
+		}
+		abortf("Failed to load share: %s", err)
+
+		properties := make(map[string]string)
+		flattenShareData(data, properties)
+		if !reveal {
+			for _, key := range []string{"join_secret", "player_secret"} {
+				if properties[key] != "" {
+					properties[key] = maskSecret(properties[key])
+				}
+			}
+		}
+		keys := make([]string, 0, len(properties))
+		for key := range properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		out := colorable.NewColorableStdout()
+		printInfoProperty(out, "ID", entry.Id, 15)
+		printInfoProperty(out, "Type", entry.Type, 15)
+		printInfoProperty(out, "Link", client.Link(entry.Id), 15)
+		if known {
+			printInfoProperty(out, "Created", entry.Created.Local().Format("2006-01-02 15:04"), 15)
+			if entry.Password {
+				printInfoProperty(out, "Password", "yes", 15)
+			}
+		}
+		for _, key := range keys {
+			printInfoProperty(out, propertyLabel(key), properties[key], 15)
+		}
+	},
+}
+
+// lookupShare returns the share with id from the share history.
+// If the share is not in the history, the type must be specified with the --type flag of cmd.
+func lookupShare(cmd *cobra.Command, id string) (shareEntry, bool) {
+	shareType, err := cmd.Flags().GetString("type")
+	abort(err)
+
+	history, err := loadShareHistory()
+	abortf("Failed to load share history: %s", err)
+	if index := findShare(history, id); index >= 0 {
+		return history[index], true
+	}
+
+	if shareType == "" {
+		abort(fmt.Errorf("share '%s' is not in the share history (use --type to specify its type)", id))
+	}
+	return shareEntry{
+		Id:   id,
+		Type: shareType,
+	}, false
+}
+
+// withSharePassword calls request without a password first and asks for the password as long as request fails with share.ErrForbidden.
+func withSharePassword(request func(password string) error) error {
+	var password string
+	for {
+		err := request(password)
+		if !errors.Is(err, share.ErrForbidden) {
+			return err
+		}
+
+		if password != "" {
+			if !isInteractive() {
+				return errors.New("wrong password")
+			}
+			cli.Error("Wrong password. Try again.")
+		}
+		password, err = readPassword("Password:")
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if password == "" {
+			return errors.New("the share is password protected")
+		}
+	}
+}
+
+// flattenShareData adds all non-object values in data and its nested objects to properties.
+func flattenShareData(data map[string]any, properties map[string]string) {
+	for key, value := range data {
+		switch v := value.(type) {
+		case map[string]any:
+			flattenShareData(v, properties)
+		case nil:
+		case string:
+			properties[key] = v
+		default:
+			encoded, err := json.Marshal(v)
+			if err == nil {
+				properties[key] = string(encoded)
+			}
+		}
+	}
+}
+
+// propertyLabel converts a JSON key like 'game_url' to a label like 'Game URL'.
+func propertyLabel(key string) string {
+	words := strings.Split(key, "_")
+	for i, w := range words {
+		switch w {
+		case "id", "url":
+			words[i] = strings.ToUpper(w)
+		default:
+			if w != "" {
+				words[i] = strings.ToUpper(w[:1]) + w[1:]
+			}
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func init() {
+	shareCmd.AddCommand(shareShowCmd)
+	shareShowCmd.Flags().StringP("type", "t", "", "The type of a share which is not in the share history. (possible values: game, spectate, session)")
+	shareShowCmd.Flags().BoolP("reveal", "r", false, "Show secrets in plain text.")
+}
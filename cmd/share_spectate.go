@@ -1,32 +1,36 @@
 package cmd
 
 import (
-	"bytes"
-	"encoding/json"
-	"net/http"
-
 	"github.com/Bananenpro/cli"
-	"github.com/code-game-project/go-utils/config"
-	"github.com/code-game-project/go-utils/external"
+	"github.com/code-game-project/codegame-cli/share"
 	"github.com/spf13/cobra"
 )
 
 // shareSpectateCmd represents the share spectate command
 var shareSpectateCmd = &cobra.Command{
-	Use:   "spectate",
-	Short: "Share a spectate link with CodeGame Share.",
-	Args:  cobra.RangeArgs(0, 2),
+	Use:               "spectate",
+	Short:             "Share a spectate link with CodeGame Share.",
+	Args:              cobra.RangeArgs(0, 2),
+	ValidArgsFunction: completeSession,
 	Run: func(cmd *cobra.Command, args []string) {
 		var gameURL string
 		var gameId string
 		var playerId string
 		var playerSecret string
 
-		fromSession, err := cli.YesNo("Select from session?", true)
-		abort(err)
+		session, fromSession := findActiveSession()
+		var err error
+		if fromSession && len(args) == 0 {
+			cli.Print("Using active session %s@%s.", session.Username, session.GameURL)
+		} else {
+			fromSession, err = cli.YesNo("Select from session?", true)
+			abort(err)
+			if fromSession {
+				session, err = selectSession(args)
+				abortf("Failed to load session: %s", err)
+			}
+		}
 		if fromSession {
-			session, err := selectSession(args)
-			abortf("Failed to load session: %s", err)
 			gameURL = session.GameURL
 			gameId = session.GameId
 			playerId = session.PlayerId
@@ -62,43 +66,25 @@ var shareSpectateCmd = &cobra.Command{
 			PlayerSecret: playerSecret,
 		}
 
-		jsonData, err := json.Marshal(data)
-		abort(err)
-
-		conf := config.Load()
-		shareURL := external.TrimURL(conf.ShareURL)
-		baseURL := external.BaseURL("http", external.IsTLS(shareURL), shareURL)
-
-		resp, err := http.Post(baseURL+"/spectate", "application/json", bytes.NewBuffer(jsonData))
-		if err != nil {
-			cli.Error("Failed to upload data: %s", err)
-			return
-		}
-		if resp.StatusCode != http.StatusCreated {
-			type response struct {
-				Error string `json:"error"`
-			}
-			var res response
-			err = json.NewDecoder(resp.Body).Decode(&res)
-			if err != nil {
-				cli.Error("Failed to decode error message: %s", err)
-			} else {
-				cli.Error(res.Error)
-			}
-			return
-		}
-
-		type response struct {
-			Id string `json:"id"`
-		}
-		var res response
-		err = json.NewDecoder(resp.Body).Decode(&res)
-		abortf("Failed to decode server response: %s", err)
+		client := newShareClient("")
+		id, token, err := client.Create(share.TypeSpectate, data)
+		abortf("Failed to upload data: %s", err)
+		recordShare(shareEntry{
+			Id:       id,
+			Type:     share.TypeSpectate,
+			Token:    token,
+			ShareURL: client.BaseURL(),
+			GameURL:  gameURL,
+			GameId:   gameId,
+		})
 		cli.Success("Success! You can spectate the game with the following link:")
-		cli.PrintColor(cli.Cyan, baseURL+"/%s", res.Id)
+		cli.PrintColor(cli.Cyan, "%s", client.Link(id))
+		printShareQR(cmd, client.Link(id))
 	},
 }
 
 func init() {
 	shareCmd.AddCommand(shareSpectateCmd)
+	shareSpectateCmd.Flags().BoolP("qr", "", false, "Print the link as a QR code.")
+	shareSpectateCmd.Flags().StringP("qr-png", "", "", "Write the link as a QR code to this PNG file.")
 }
@@ -0,0 +1,362 @@
+package cmd
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Bananenpro/cli"
+	"github.com/code-game-project/go-utils/server"
+	"github.com/gorilla/websocket"
+	"github.com/mattn/go-colorable"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// spectateCmd represents the spectate command
+var spectateCmd = &cobra.Command{
+	Use:               "spectate",
+	Short:             "Watch the events of a game live in the terminal.",
+	Args:              cobra.RangeArgs(0, 2),
+	ValidArgsFunction: completeGameId,
+	Run: func(cmd *cobra.Command, args []string) {
+		fromSession, err := cmd.Flags().GetBool("session")
+		abort(err)
+		filter, err := cmd.Flags().GetStringSlice("filter")
+		abort(err)
+
+		var gameURL string
+		var gameId string
+		if fromSession {
+			session, err := selectSession(args)
+			abortf("Failed to load session: %s", err)
+			gameURL = session.GameURL
+			gameId = session.GameId
+		} else {
+			if len(args) > 0 {
+				gameURL = args[0]
+				if len(args) > 1 {
+					gameId = args[1]
+				}
+			} else if gameURL = findGameURL(); gameURL != "" {
+				cli.Print("Game URL: %s", gameURL)
+			} else {
+				gameURL, err = cli.Input("Game URL:")
+				abort(err)
+			}
+			if gameId == "" {
+				gameId, err = cli.Input("Game ID:")
+				abort(err)
+			}
+		}
+
+		api, err := server.NewAPI(gameURL)
+		abort(err)
+
+		conn, _, err := websocket.DefaultDialer.Dial(websocketURL(api, "/games/"+gameId+"/spectate"), nil)
+		abortf("Failed to spectate game: %s", err)
+		defer conn.Close()
+
+		s := &spectator{
+			api:    api,
+			gameId: gameId,
+			out:    colorable.NewColorableStdout(),
+			counts: make(map[string]int),
+		}
+		s.setFilter(filter)
+		abort(s.run(conn))
+	},
+}
+
+var spectateColors = []cli.Color{cli.Cyan, cli.Green, cli.Yellow, cli.Blue, cli.Magenta, cli.CyanBold, cli.GreenBold, cli.YellowBold, cli.BlueBold, cli.MagentaBold}
+
+var spectateHelp = `Keys:
+  p, space  Pause/resume the event feed.
+  f         Filter events by name. (comma separated, empty to show all)
+            Events are held while typing and shown afterwards.
+  s         Show event counts and the player list.
+  h         Show this help.
+  q         Quit.`
+
+type spectator struct {
+	api    *server.API
+	gameId string
+
+	lock   sync.Mutex
+	out    io.Writer
+	raw    bool
+	paused bool
+	queue  []wsMessage
+	counts map[string]int
+	filter []string
+	// editing is true while the user types a new filter. Events are queued in the meantime.
+	editing bool
+	input   []byte
+}
+
+// run prints all events received on conn until the connection is closed or the user quits.
+// Keyboard controls are only available if stdin is a terminal.
+func (s *spectator) run(conn *websocket.Conn) error {
+	keys := make(chan byte)
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		state, err := term.MakeRaw(int(os.Stdin.Fd()))
+		if err != nil {
+			return err
+		}
+		defer term.Restore(int(os.Stdin.Fd()), state)
+		s.raw = true
+
+		go func() {
+			buffer := make([]byte, 1)
+			for {
+				_, err := os.Stdin.Read(buffer)
+				if err != nil {
+					close(keys)
+					return
+				}
+				keys <- buffer[0]
+			}
+		}()
+		s.print("%sSpectating %s. Press 'h' for help.%s\n", cli.Green, s.gameId, cli.Reset)
+	} else {
+		s.print("%sSpectating %s.%s\n", cli.Green, s.gameId, cli.Reset)
+	}
+
+	events := make(chan wsMessage)
+	go func() {
+		defer close(events)
+		for {
+			var msg wsMessage
+			err := conn.ReadJSON(&msg)
+			if err != nil {
+				return
+			}
+			events <- msg
+		}
+	}()
+
+	for {
+		select {
+		case msg, ok := <-events:
+			if !ok {
+				s.print("%sConnection closed.%s\n", cli.Red, cli.Reset)
+				s.printStats()
+				return nil
+			}
+			s.handleEvent(msg)
+		case key, ok := <-keys:
+			if !ok {
+				keys = nil
+				continue
+			}
+			if s.editing {
+				s.editFilter(key)
+				continue
+			}
+			switch key {
+			case 'q', 3, 4: // q, Ctrl+C, Ctrl+D
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+				return nil
+			case 'p', ' ':
+				s.togglePause()
+			case 'f':
+				s.startFilterInput()
+			case 's':
+				s.printStats()
+			case 'h', '?':
+				s.print("%s\n", spectateHelp)
+			}
+		}
+	}
+}
+
+func (s *spectator) handleEvent(msg wsMessage) {
+	s.lock.Lock()
+	s.counts[msg.Name]++
+	if s.paused || s.editing {
+		s.queue = append(s.queue, msg)
+		s.lock.Unlock()
+		return
+	}
+	s.lock.Unlock()
+	s.printEvent(msg)
+}
+
+func (s *spectator) togglePause() {
+	s.lock.Lock()
+	s.paused = !s.paused
+	paused := s.paused
+	queue := s.queue
+	s.queue = nil
+	s.lock.Unlock()
+
+	if paused {
+		s.print("%s-- paused --%s\n", cli.Yellow, cli.Reset)
+		return
+	}
+	s.print("%s-- resumed (%d queued events) --%s\n", cli.Yellow, len(queue), cli.Reset)
+	for _, msg := range queue {
+		s.printEvent(msg)
+	}
+}
+
+func (s *spectator) setFilter(filter []string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.filter = make([]string, 0, len(filter))
+	for _, f := range filter {
+		if f = strings.TrimSpace(f); f != "" {
+			s.filter = append(s.filter, f)
+		}
+	}
+}
+
+// startFilterInput starts reading a new filter from the key presses of the main loop.
+// Incoming events are queued until the filter is submitted or canceled.
+func (s *spectator) startFilterInput() {
+	s.lock.Lock()
+	s.editing = true
+	s.input = s.input[:0]
+	s.lock.Unlock()
+	s.print("%s-- events are held while typing --%s\nFilter: ", cli.Yellow, cli.Reset)
+}
+
+// editFilter handles a key press while the filter is being typed.
+func (s *spectator) editFilter(key byte) {
+	switch key {
+	case '\r', '\n':
+		s.print("\n")
+		s.finishFilterInput(true)
+	case 3, 27: // Ctrl+C, Escape
+		s.print("\n")
+		s.finishFilterInput(false)
+	case 127, 8: // Backspace
+		if len(s.input) > 0 {
+			s.input = s.input[:len(s.input)-1]
+			s.print("\b \b")
+		}
+	default:
+		if key >= 32 && key < 127 {
+			s.input = append(s.input, key)
+			s.print("%c", key)
+		}
+	}
+}
+
+// finishFilterInput applies the typed filter if apply is true and prints the events received while typing, unless the feed is paused.
+func (s *spectator) finishFilterInput(apply bool) {
+	if apply {
+		s.setFilter(strings.Split(string(s.input), ","))
+	}
+	s.lock.Lock()
+	s.editing = false
+	var queue []wsMessage
+	if !s.paused {
+		queue = s.queue
+		s.queue = nil
+	}
+	paused := s.paused
+	s.lock.Unlock()
+
+	if paused {
+		return
+	}
+	s.print("%s-- resumed (%d queued events) --%s\n", cli.Yellow, len(queue), cli.Reset)
+	for _, msg := range queue {
+		s.printEvent(msg)
+	}
+}
+
+func (s *spectator) printEvent(msg wsMessage) {
+	s.lock.Lock()
+	filter := s.filter
+	s.lock.Unlock()
+
+	if len(filter) > 0 {
+		match := false
+		for _, f := range filter {
+			if strings.Contains(msg.Name, f) {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return
+		}
+	}
+
+	data := string(msg.Data)
+	if data == "" {
+		data = "{}"
+	}
+	s.print("%s %s%s%s %s\n", time.Now().Format("15:04:05"), eventColor(msg.Name), msg.Name, cli.Reset, data)
+}
+
+func (s *spectator) printStats() {
+	s.lock.Lock()
+	names := make([]string, 0, len(s.counts))
+	for name := range s.counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if s.counts[names[i]] == s.counts[names[j]] {
+			return names[i] < names[j]
+		}
+		return s.counts[names[i]] > s.counts[names[j]]
+	})
+	stats := make([]string, len(names))
+	total := 0
+	for i, name := range names {
+		stats[i] = fmt.Sprintf("  %s%s%s: %d", eventColor(name), name, cli.Reset, s.counts[name])
+		total += s.counts[name]
+	}
+	s.lock.Unlock()
+
+	s.print("%sEvents (%d):%s\n", cli.CyanBold, total, cli.Reset)
+	if len(stats) > 0 {
+		s.print("%s\n", strings.Join(stats, "\n"))
+	}
+
+	players, err := s.api.GetPlayers(s.gameId)
+	if err != nil {
+		s.print("%sFailed to fetch players: %s%s\n", cli.Red, err, cli.Reset)
+		return
+	}
+	usernames := make([]string, 0, len(players))
+	for id, username := range players {
+		usernames = append(usernames, fmt.Sprintf("  %s (%s)", username, id))
+	}
+	sort.Strings(usernames)
+	s.print("%sPlayers (%d):%s\n", cli.CyanBold, len(players), cli.Reset)
+	if len(usernames) > 0 {
+		s.print("%s\n", strings.Join(usernames, "\n"))
+	}
+}
+
+func (s *spectator) print(format string, a ...any) {
+	text := fmt.Sprintf(format, a...)
+	if s.raw {
+		text = strings.ReplaceAll(text, "\n", "\r\n")
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	fmt.Fprint(s.out, text)
+}
+
+// eventColor deterministically assigns a color to an event name.
+func eventColor(name string) cli.Color {
+	hash := fnv.New32a()
+	hash.Write([]byte(name))
+	return spectateColors[hash.Sum32()%uint32(len(spectateColors))]
+}
+
+func init() {
+	rootCmd.AddCommand(spectateCmd)
+	spectateCmd.Flags().BoolP("session", "s", false, "Select the game from a stored session.")
+	spectateCmd.Flags().StringSliceP("filter", "f", nil, "Only show events whose name contains one of the specified values.")
+}
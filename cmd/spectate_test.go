@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSpectatorFilterInput(t *testing.T) {
+	var out bytes.Buffer
+	s := &spectator{out: &out, counts: make(map[string]int)}
+
+	s.startFilterInput()
+	for _, key := range []byte("move,x") {
+		s.editFilter(key)
+	}
+	s.editFilter(127)
+	s.handleEvent(wsMessage{Name: "move"})
+	s.handleEvent(wsMessage{Name: "chat"})
+	if strings.Contains(out.String(), "resumed") {
+		t.Fatalf("events printed while typing the filter:\n%s", out.String())
+	}
+	if s.counts["move"] != 1 || s.counts["chat"] != 1 {
+		t.Errorf("counts = %v, want events to be counted while typing", s.counts)
+	}
+
+	s.editFilter('\r')
+	if len(s.filter) != 1 || s.filter[0] != "move" {
+		t.Errorf("filter = %q, want [move]", s.filter)
+	}
+	_, printed, _ := strings.Cut(out.String(), "resumed")
+	if !strings.Contains(printed, "move") {
+		t.Errorf("queued event not printed after the filter was submitted:\n%s", out.String())
+	}
+	if strings.Contains(printed, "chat") {
+		t.Errorf("queued event not matching the new filter was printed:\n%s", out.String())
+	}
+	if len(s.queue) != 0 {
+		t.Errorf("queue = %v, want it to be flushed", s.queue)
+	}
+}
+
+func TestSpectatorFilterInputCanceled(t *testing.T) {
+	var out bytes.Buffer
+	s := &spectator{out: &out, counts: make(map[string]int), filter: []string{"chat"}}
+
+	s.togglePause()
+	s.startFilterInput()
+	s.editFilter('x')
+	s.handleEvent(wsMessage{Name: "chat"})
+	s.editFilter(27)
+	if len(s.filter) != 1 || s.filter[0] != "chat" {
+		t.Errorf("filter = %q, want the filter to be kept after canceling", s.filter)
+	}
+	if len(s.queue) != 1 {
+		t.Errorf("queue length = %d, want the event to stay queued while paused", len(s.queue))
+	}
+}
@@ -1,11 +1,8 @@
 package cmd
 
 import (
-	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
-	"strings"
 
 	"github.com/code-game-project/go-utils/cgfile"
 	"github.com/code-game-project/go-utils/cggenevents"
@@ -97,6 +94,8 @@ func updateClient(config *cgfile.CodeGameFileData) error {
 	case "js", "ts":
 		updateData.LibraryVersion = external.LibraryVersionFromCGVersion("code-game-project", "javascript-client", info.CGVersion)
 		err = modules.ExecuteUpdate(updateData, config)
+	case "py", "rs":
+		// There is no language module, which could update a client library. Only the event definitions are updated.
 	default:
 		err = fmt.Errorf("'update' is not supported for '%s'", config.Lang)
 	}
@@ -104,33 +103,16 @@ func updateClient(config *cgfile.CodeGameFileData) error {
 		return err
 	}
 
-	if config.Lang == "cs" || config.Lang == "go" || config.Lang == "java" || config.Lang == "ts" {
-		eventsOutput := config.Game
-		switch config.Lang {
-		case "cs":
-			eventsOutput = strings.ReplaceAll(strings.Title(strings.ReplaceAll(strings.ReplaceAll(eventsOutput, "_", " "), "-", " ")), " ", "")
-		case "go":
-			eventsOutput = strings.ReplaceAll(strings.ReplaceAll(eventsOutput, "-", ""), "_", "")
-		case "java":
-			packageConf, ok := config.LangConfig["package"]
-			if !ok {
-				return errors.New("Missing language config field `package` in .codegame.json!")
-			}
-			packageName := packageConf.(string)
-			if packageConf == "" {
-				return errors.New("Empty language config field `package` in .codegame.json!")
-			}
-			gameDir := filepath.Join("src", "main", "java")
-			pkgDir := filepath.Join(strings.Split(packageName, ".")...)
-			eventsOutput = filepath.Join(gameDir, pkgDir, strings.ReplaceAll(strings.ReplaceAll(strings.ToLower(eventsOutput), "_", ""), "-", ""))
-		case "ts":
-			eventsOutput = filepath.Join("src", eventsOutput)
-		}
-		err = cggenevents.CGGenEvents(cgeVersion, eventsOutput, api.BaseURL(), config.Lang)
-	}
+	eventsOutput, err := eventsOutputDir(config.Lang, config.Game, config.LangConfig)
 	if err != nil {
 		return err
 	}
+	if eventsOutput != "" {
+		err = cggenevents.CGGenEvents(cgeVersion, eventsOutput, api.BaseURL(), config.Lang)
+		if err != nil {
+			return err
+		}
+	}
 
 	config.GameVersion = info.Version
 	return config.Write("")
@@ -145,6 +127,12 @@ func updateServer(config *cgfile.CodeGameFileData) error {
 	switch config.Lang {
 	case "go":
 		return modules.ExecuteUpdate(updateData, config)
+	case "java", "ts":
+		err := modules.ExecuteUpdate(updateData, config)
+		if err != nil {
+			return err
+		}
+		return generateServerEvents(config, "")
 	default:
 		return fmt.Errorf("'update' is not supported for '%s'", config.Lang)
 	}
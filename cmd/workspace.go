@@ -0,0 +1,321 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Bananenpro/cli"
+	"github.com/code-game-project/go-utils/cgfile"
+	"github.com/code-game-project/go-utils/cggenevents"
+	"github.com/code-game-project/go-utils/config"
+)
+
+const workspaceFileName = ".codegame-workspace.json"
+
+// initialGameVersion is the game version of the server of a new workspace.
+const initialGameVersion = "0.1.0"
+
+// workspaceFile describes a workspace consisting of a game server and clients in subdirectories.
+type workspaceFile struct {
+	Name string `json:"name"`
+	// Members are the directories of the projects relative to the workspace root.
+	Members []string `json:"members"`
+}
+
+func (w workspaceFile) write(dir string) error {
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, workspaceFileName), data, 0o644)
+}
+
+// findWorkspaceRoot returns the first parent directory of the working directory which contains a workspace file.
+func findWorkspaceRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, workspaceFileName)); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", errors.New("not in a workspace")
+		}
+		dir = parent
+	}
+}
+
+func loadWorkspaceFile(root string) (workspaceFile, error) {
+	var workspace workspaceFile
+	data, err := os.ReadFile(filepath.Join(root, workspaceFileName))
+	if err != nil {
+		return workspace, err
+	}
+	err = json.Unmarshal(data, &workspace)
+	return workspace, err
+}
+
+// newWorkspace creates a game server and clients in clientLanguages, which connect to the server on the dev port, in the current directory.
+func newWorkspace(projectName, serverLanguage string, clientLanguages []string) error {
+	if len(clientLanguages) == 0 {
+		if !isInteractive() {
+			return errors.New("no client languages specified (use --clients)")
+		}
+		languages := supportedLanguages["client"]
+		names := make([]string, len(languages))
+		for i, l := range languages {
+			names[i] = languageNames[l]
+		}
+		for len(clientLanguages) == 0 {
+			selected, err := cli.MultiSelect("Client languages:", names, nil)
+			if err != nil {
+				return err
+			}
+			for i, s := range selected {
+				if s {
+					clientLanguages = append(clientLanguages, languages[i])
+				}
+			}
+			if len(clientLanguages) == 0 {
+				cli.Error("Select at least one client language.")
+			}
+		}
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	workspace := workspaceFile{
+		Name:    projectName,
+		Members: []string{"server"},
+	}
+
+	err = createWorkspaceMember(root, "server", func() error {
+		return newServer(projectName, serverLanguage)
+	})
+	if err != nil {
+		return err
+	}
+
+	cgePath := filepath.Join(root, "server", "events.cge")
+	cge, err := os.ReadFile(cgePath)
+	if err != nil {
+		return err
+	}
+	cgeVersion, err := cggenevents.ParseCGEVersion(string(cge))
+	if err != nil {
+		return err
+	}
+
+	// The clients belong to the game of the server and are created for its current version.
+	server, err := cgfile.LoadCodeGameFile(filepath.Join(root, "server"))
+	if err != nil {
+		return fmt.Errorf("Failed to open .codegame.json of the server: %w", err)
+	}
+	if server.GameVersion == "" {
+		server.GameVersion = initialGameVersion
+		err = server.Write(filepath.Join(root, "server"))
+		if err != nil {
+			return fmt.Errorf("Failed to write .codegame.json of the server: %w", err)
+		}
+	}
+
+	url := fmt.Sprintf("localhost:%d", config.Load().DevPort)
+	for _, language := range clientLanguages {
+		member := "client-" + language
+		cli.PrintColor(cli.Cyan, "Creating %s client in '%s/'...", languageNames[language], member)
+		err = createWorkspaceMember(root, member, func() error {
+			return createClient(language, server.Game, server.GameVersion, url, "", cgeVersion, cgePath)
+		})
+		if err != nil {
+			return err
+		}
+		workspace.Members = append(workspace.Members, member)
+	}
+
+	return workspace.write(root)
+}
+
+// createWorkspaceMember runs create in the new directory member of the workspace in root.
+func createWorkspaceMember(root, member string, create func() error) error {
+	dir := filepath.Join(root, member)
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return err
+	}
+	err = os.Chdir(dir)
+	if err != nil {
+		return err
+	}
+	defer os.Chdir(root)
+	return create()
+}
+
+// runWorkspace starts the game server of the current workspace on the dev port, waits until it is ready
+// and runs all clients with args. The server is stopped after all clients have exited.
+func runWorkspace(args []string) {
+	root, err := findWorkspaceRoot()
+	abort(err)
+	workspace, err := loadWorkspaceFile(root)
+	abortf("Failed to load workspace file: %s", err)
+
+	var serverDir string
+	clientDirs := make([]string, 0, len(workspace.Members))
+	for _, member := range workspace.Members {
+		dir := filepath.Join(root, member)
+		data, err := cgfile.LoadCodeGameFile(dir)
+		abortf("Failed to load .codegame.json of workspace member: %s", err)
+		switch data.Type {
+		case "server":
+			if serverDir != "" {
+				abort(errors.New("the workspace contains multiple servers"))
+			}
+			serverDir = dir
+		case "client":
+			clientDirs = append(clientDirs, dir)
+		}
+	}
+	if serverDir == "" {
+		abort(errors.New("the workspace does not contain a server"))
+	}
+
+	port := config.Load().DevPort
+	if available := findAvailablePort(port); available != port {
+		abort(fmt.Errorf("the dev port %d is already in use", port))
+	}
+
+	executable, err := os.Executable()
+	abort(err)
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	cli.PrintColor(cli.Cyan, "Starting server in '%s'...", serverDir)
+	serverCmd := workspaceRunCommand(executable, serverDir, nil)
+	serverCmd.Env = append(serverCmd.Env, fmt.Sprintf("CG_PORT=%d", port))
+	abortf("Failed to start server: %s", serverCmd.Start())
+	serverExited := make(chan error, 1)
+	go func() {
+		serverExited <- serverCmd.Wait()
+	}()
+	stopServer := func() {
+		if serverCmd.Process.Signal(os.Interrupt) != nil {
+			serverCmd.Process.Kill()
+		}
+		select {
+		case <-serverExited:
+		case <-time.After(10 * time.Second):
+			serverCmd.Process.Kill()
+		}
+	}
+
+	err = waitForServer(fmt.Sprintf("http://localhost:%d/api/info", port), serverExited, interrupt)
+	if err != nil {
+		stopServer()
+		abortf("Failed to start server: %s", err)
+	}
+	cli.Success("The server is ready on port %d.", port)
+
+	var wg sync.WaitGroup
+	clientsExited := make(chan struct{})
+	failed := false
+	var lock sync.Mutex
+	for _, dir := range clientDirs {
+		cli.PrintColor(cli.Cyan, "Starting client in '%s'...", dir)
+		clientCmd := workspaceRunCommand(executable, dir, args)
+		err := clientCmd.Start()
+		if err != nil {
+			cli.Error("Failed to start client in '%s': %s", dir, err)
+			lock.Lock()
+			failed = true
+			lock.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func(dir string) {
+			defer wg.Done()
+			if err := clientCmd.Wait(); err != nil {
+				cli.Error("Client in '%s' exited: %s", dir, err)
+				lock.Lock()
+				failed = true
+				lock.Unlock()
+			}
+		}(dir)
+	}
+	go func() {
+		wg.Wait()
+		close(clientsExited)
+	}()
+
+	select {
+	case <-clientsExited:
+	case err := <-serverExited:
+		serverExited <- err
+		cli.Error("The server exited unexpectedly.")
+		lock.Lock()
+		failed = true
+		lock.Unlock()
+		wg.Wait()
+	case <-interrupt:
+		wg.Wait()
+	}
+	stopServer()
+
+	lock.Lock()
+	defer lock.Unlock()
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// workspaceRunCommand returns a command, which executes `codegame run` with args in dir.
+func workspaceRunCommand(executable, dir string, args []string) *exec.Cmd {
+	cmd := exec.Command(executable, append([]string{"run"}, args...)...)
+	cmd.Dir = dir
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	return cmd
+}
+
+// waitForServer polls infoURL until the server responds or exits.
+func waitForServer(infoURL string, serverExited chan error, interrupt chan os.Signal) error {
+	client := &http.Client{Timeout: time.Second}
+	timeout := time.After(5 * time.Minute)
+	for {
+		resp, err := client.Get(infoURL)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+		select {
+		case err := <-serverExited:
+			serverExited <- err
+			if err == nil {
+				return errors.New("the server exited")
+			}
+			return err
+		case <-interrupt:
+			return cli.ErrCanceled
+		case <-timeout:
+			return errors.New("the server did not respond within 5 minutes")
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,293 @@
+// Package share implements a client for CodeGame Share.
+package share
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/code-game-project/go-utils/external"
+)
+
+// Share types supported by CodeGame Share.
+const (
+	TypeGame     = "game"
+	TypeSpectate = "spectate"
+	TypeSession  = "session"
+)
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrForbidden     = errors.New("wrong or missing password")
+	ErrRateLimited   = errors.New("too many requests")
+	ErrNotSupported  = errors.New("not supported by the server")
+	ErrInvalidResult = errors.New("invalid server response")
+)
+
+// Error is returned for all unsuccessful responses of the Share server.
+// It matches ErrNotFound, ErrForbidden, ErrRateLimited and ErrNotSupported with errors.Is depending on the status code.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return fmt.Sprintf("unexpected response: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden || e.StatusCode == http.StatusUnauthorized
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrNotSupported:
+		return e.StatusCode == http.StatusMethodNotAllowed || e.StatusCode == http.StatusNotImplemented
+	}
+	return false
+}
+
+// Options configure a Client. Zero values are replaced with their defaults.
+type Options struct {
+	// Timeout limits the duration of a single request. Default: 10s
+	Timeout time.Duration
+	// MaxRetries is the number of times a request is retried after 429 or 5xx responses. Negative values disable retries. Default: 4
+	// POST requests are only retried after 429 responses because the server might have already created the share before responding with 5xx.
+	MaxRetries int
+	// MinBackoff is the delay before the first retry, which is doubled with every further retry. Default: 500ms
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum delay between two retries including delays requested with Retry-After. Default: 30s
+	MaxBackoff time.Duration
+	// Proxy is the URL of the proxy server to use. If empty, the proxy is determined by the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+	Proxy string
+}
+
+// Client sends requests to a CodeGame Share server.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	options    Options
+}
+
+// NewClient returns a client for the Share server at shareURL.
+// If shareURL does not include the protocol, https is used if the server supports TLS.
+func NewClient(shareURL string, options Options) (*Client, error) {
+	if options.Timeout == 0 {
+		options.Timeout = 10 * time.Second
+	}
+	if options.MaxRetries == 0 {
+		options.MaxRetries = 4
+	}
+	if options.MinBackoff == 0 {
+		options.MinBackoff = 500 * time.Millisecond
+	}
+	if options.MaxBackoff == 0 {
+		options.MaxBackoff = 30 * time.Second
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if options.Proxy != "" {
+		proxyURL, err := url.Parse(options.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	} else {
+		transport.Proxy = http.ProxyFromEnvironment
+	}
+
+	var baseURL string
+	if strings.HasPrefix(shareURL, "http://") || strings.HasPrefix(shareURL, "https://") {
+		baseURL = strings.TrimSuffix(shareURL, "/")
+	} else {
+		shareURL = external.TrimURL(shareURL)
+		baseURL = external.BaseURL("http", external.IsTLS(shareURL), shareURL)
+	}
+
+	return &Client{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout:   options.Timeout,
+			Transport: transport,
+		},
+		options: options,
+	}, nil
+}
+
+// BaseURL returns the base URL of the Share server including the protocol.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Link returns the link to the share with id.
+func (c *Client) Link(id string) string {
+	return c.baseURL + "/" + id
+}
+
+// Create uploads data as a new share of shareType and returns its id and its owner token, which is required to delete the share.
+// The token is empty if the server does not issue owner tokens.
+func (c *Client) Create(shareType string, data any) (id, token string, err error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return "", "", err
+	}
+
+	resp, err := c.do(http.MethodPost, "/"+shareType, nil, body)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return "", "", decodeError(resp)
+	}
+
+	type response struct {
+		Id    string `json:"id"`
+		Token string `json:"token"`
+	}
+	var res response
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	if err != nil || res.Id == "" {
+		return "", "", ErrInvalidResult
+	}
+	return res.Id, res.Token, nil
+}
+
+// Get decodes the share with id into v. password is only sent if it is not empty.
+func (c *Client) Get(shareType, id, password string, v any) error {
+	var header http.Header
+	if password != "" {
+		header = http.Header{"Password": {password}}
+	}
+	resp, err := c.do(http.MethodGet, sharePath(shareType, id), header, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return decodeError(resp)
+	}
+	err = json.NewDecoder(resp.Body).Decode(v)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidResult, err)
+	}
+	return nil
+}
+
+// Delete deletes the share with id using the owner token returned by Create.
+// It returns ErrForbidden if the token is wrong and ErrNotSupported if the server does not support deleting shares.
+func (c *Client) Delete(shareType, id, token string) error {
+	var header http.Header
+	if token != "" {
+		header = http.Header{"Owner-Token": {token}}
+	}
+	resp, err := c.do(http.MethodDelete, sharePath(shareType, id), header, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return decodeError(resp)
+	}
+	return nil
+}
+
+func sharePath(shareType, id string) string {
+	return fmt.Sprintf("/%s?type=%s", url.PathEscape(id), url.QueryEscape(shareType))
+}
+
+// do sends a request and retries it with exponential backoff on 429 and, unless it is a POST request, 5xx responses.
+func (c *Client) do(method, path string, header http.Header, body []byte) (*http.Response, error) {
+	backoff := c.options.MinBackoff
+	for attempt := 0; ; attempt++ {
+		var bodyReader io.Reader
+		if body != nil {
+			bodyReader = bytes.NewReader(body)
+		}
+		request, err := http.NewRequest(method, c.baseURL+path, bodyReader)
+		if err != nil {
+			return nil, err
+		}
+		for name, values := range header {
+			request.Header[name] = values
+		}
+		request.Header.Set("Accept", "application/json")
+		if body != nil {
+			request.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := c.httpClient.Do(request)
+		if err != nil {
+			return nil, err
+		}
+		if attempt >= c.options.MaxRetries || !retryable(method, resp.StatusCode) {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		delay := backoff
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			delay = retryAfter
+		}
+		if delay > c.options.MaxBackoff {
+			delay = c.options.MaxBackoff
+		}
+		time.Sleep(delay)
+		backoff *= 2
+	}
+}
+
+// retryable reports whether a request with method should be retried after a response with statusCode.
+// A rate limited request has not been processed by the server, so it can always be retried.
+// POST is not idempotent and is therefore never retried after 5xx responses to avoid creating duplicate shares.
+func retryable(method string, statusCode int) bool {
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if method == http.MethodPost {
+		return false
+	}
+	return statusCode >= 500 && statusCode != http.StatusNotImplemented
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which contains either seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+func decodeError(resp *http.Response) error {
+	type response struct {
+		Error string `json:"error"`
+	}
+	var res response
+	json.NewDecoder(resp.Body).Decode(&res)
+	return &Error{
+		StatusCode: resp.StatusCode,
+		Message:    res.Error,
+	}
+}
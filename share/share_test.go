@@ -0,0 +1,338 @@
+package share
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client for a local stand-in of the Share server, which responds with handler.
+func newTestClient(t *testing.T, handler http.HandlerFunc, options Options) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	if options.MinBackoff == 0 {
+		options.MinBackoff = time.Millisecond
+	}
+	client, err := NewClient(server.URL, options)
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+	return client
+}
+
+// countingHandler responds with statusCodes in order and with the last one after that. It counts the requests in count.
+func countingHandler(count *int32, header http.Header, statusCodes ...int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(count, 1)) - 1
+		if n >= len(statusCodes) {
+			n = len(statusCodes) - 1
+		}
+		for name, values := range header {
+			w.Header()[name] = values
+		}
+		w.WriteHeader(statusCodes[n])
+		if statusCodes[n] == http.StatusOK {
+			fmt.Fprint(w, `{"name":"test"}`)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCodes []int
+		maxRetries  int
+		wantStatus  int
+		wantCount   int32
+	}{
+		{"success", []int{200}, 4, 200, 1},
+		{"429 then success", []int{429, 200}, 4, 200, 2},
+		{"503 then success", []int{503, 502, 200}, 4, 200, 3},
+		{"gives up after MaxRetries", []int{500}, 2, 500, 3},
+		{"retries disabled", []int{429}, -1, 429, 1},
+		{"404 is not retried", []int{404}, 4, 404, 1},
+		{"501 is not retried", []int{501}, 4, 501, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var count int32
+			client := newTestClient(t, countingHandler(&count, nil, test.statusCodes...), Options{MaxRetries: test.maxRetries})
+			resp, err := client.do(http.MethodGet, "/id?type=game", nil, nil)
+			if err != nil {
+				t.Fatalf("do: %s", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != test.wantStatus {
+				t.Errorf("status code = %d, want %d", resp.StatusCode, test.wantStatus)
+			}
+			if count != test.wantCount {
+				t.Errorf("requests = %d, want %d", count, test.wantCount)
+			}
+		})
+	}
+}
+
+func TestRetryPost(t *testing.T) {
+	var count int32
+	client := newTestClient(t, countingHandler(&count, nil, 500), Options{})
+	_, _, err := client.Create(TypeGame, map[string]string{"game_url": "localhost"})
+	if err == nil {
+		t.Fatal("Create succeeded, want error")
+	}
+	if count != 1 {
+		t.Errorf("POST requests after 500 = %d, want 1", count)
+	}
+
+	count = 0
+	client = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&count, 1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":"abc","token":"owner"}`)
+	}, Options{})
+	id, token, err := client.Create(TypeGame, map[string]string{"game_url": "localhost"})
+	if err != nil {
+		t.Fatalf("Create: %s", err)
+	}
+	if id != "abc" {
+		t.Errorf("id = %q, want %q", id, "abc")
+	}
+	if token != "owner" {
+		t.Errorf("token = %q, want %q", token, "owner")
+	}
+	if count != 2 {
+		t.Errorf("POST requests after 429 = %d, want 2", count)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		retryAfter string
+	}{
+		{"seconds", "3600"},
+		{"http date", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var count int32
+			header := http.Header{"Retry-After": {test.retryAfter}}
+			client := newTestClient(t, countingHandler(&count, header, 429, 200), Options{MaxBackoff: 50 * time.Millisecond})
+			start := time.Now()
+			resp, err := client.do(http.MethodGet, "/id?type=game", nil, nil)
+			if err != nil {
+				t.Fatalf("do: %s", err)
+			}
+			resp.Body.Close()
+			elapsed := time.Since(start)
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("status code = %d, want 200", resp.StatusCode)
+			}
+			if elapsed < 50*time.Millisecond || elapsed > 5*time.Second {
+				t.Errorf("elapsed = %s, want the delay to be capped at MaxBackoff (50ms)", elapsed)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0, true},
+	}
+	for _, test := range tests {
+		got, ok := parseRetryAfter(test.value)
+		if got != test.want || ok != test.wantOK {
+			t.Errorf("parseRetryAfter(%q) = %s, %t, want %s, %t", test.value, got, ok, test.want, test.wantOK)
+		}
+	}
+
+	got, ok := parseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	if !ok || got <= 0 || got > time.Minute {
+		t.Errorf("parseRetryAfter(now+1m) = %s, %t, want (0, 1m], true", got, ok)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	done := make(chan struct{})
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+		}
+	}, Options{Timeout: 50 * time.Millisecond, MaxRetries: -1})
+	defer close(done)
+
+	start := time.Now()
+	var v any
+	err := client.Get(TypeGame, "id", "", &v)
+	if err == nil {
+		t.Fatal("Get succeeded, want timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("elapsed = %s, want the request to time out after 50ms", elapsed)
+	}
+}
+
+func TestErrorIs(t *testing.T) {
+	targets := []error{ErrNotFound, ErrForbidden, ErrRateLimited, ErrNotSupported}
+	tests := []struct {
+		statusCode int
+		want       error
+	}{
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusUnauthorized, ErrForbidden},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusMethodNotAllowed, ErrNotSupported},
+		{http.StatusNotImplemented, ErrNotSupported},
+		{http.StatusBadRequest, nil},
+		{http.StatusInternalServerError, nil},
+	}
+	for _, test := range tests {
+		err := fmt.Errorf("wrapped: %w", &Error{StatusCode: test.statusCode})
+		for _, target := range targets {
+			if got := errors.Is(err, target); got != (target == test.want) {
+				t.Errorf("errors.Is(%d, %q) = %t, want %t", test.statusCode, target, got, !got)
+			}
+		}
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Password") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error":"wrong password"}`)
+			return
+		}
+		fmt.Fprint(w, `{"name":"test"}`)
+	}, Options{})
+
+	var v struct {
+		Name string `json:"name"`
+	}
+	err := client.Get(TypeSession, "id", "wrong", &v)
+	if !errors.Is(err, ErrForbidden) {
+		t.Fatalf("Get with wrong password = %v, want ErrForbidden", err)
+	}
+	if err.Error() != "wrong password" {
+		t.Errorf("error message = %q, want %q", err.Error(), "wrong password")
+	}
+
+	err = client.Get(TypeSession, "id", "secret", &v)
+	if err != nil {
+		t.Fatalf("Get with password: %s", err)
+	}
+	if v.Name != "test" {
+		t.Errorf("name = %q, want %q", v.Name, "test")
+	}
+}
+
+func TestDelete(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if r.Header.Get("Owner-Token") != "owner" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}, Options{})
+
+	if err := client.Delete(TypeGame, "id", "owner"); err != nil {
+		t.Errorf("Delete with owner token: %s", err)
+	}
+	if err := client.Delete(TypeGame, "id", ""); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Delete without owner token = %v, want ErrForbidden", err)
+	}
+}
+
+func TestInvalidResult(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"ID":""}`)
+			return
+		}
+		fmt.Fprint(w, `<html>not json</html>`)
+	}, Options{})
+
+	var v any
+	err := client.Get(TypeGame, "id", "", &v)
+	if !errors.Is(err, ErrInvalidResult) {
+		t.Errorf("Get with invalid body = %v, want ErrInvalidResult", err)
+	}
+	_, _, err = client.Create(TypeGame, map[string]string{})
+	if !errors.Is(err, ErrInvalidResult) {
+		t.Errorf("Create without id = %v, want ErrInvalidResult", err)
+	}
+}
+
+func TestProxy(t *testing.T) {
+	var proxied int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&proxied, 1)
+		if r.URL.Host != "share.invalid" {
+			t.Errorf("proxied host = %q, want %q", r.URL.Host, "share.invalid")
+		}
+		fmt.Fprint(w, `{"name":"test"}`)
+	}))
+	defer proxy.Close()
+
+	client, err := NewClient("http://share.invalid", Options{Proxy: proxy.URL, MaxRetries: -1})
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+	var v any
+	err = client.Get(TypeGame, "id", "", &v)
+	if err != nil {
+		t.Fatalf("Get through proxy: %s", err)
+	}
+	if proxied != 1 {
+		t.Errorf("proxied requests = %d, want 1", proxied)
+	}
+
+	_, err = NewClient("http://share.invalid", Options{Proxy: "://invalid"})
+	if err == nil {
+		t.Error("NewClient with invalid proxy URL succeeded, want error")
+	}
+
+	client, err = NewClient("http://share.invalid", Options{})
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+	transport := client.httpClient.Transport.(*http.Transport)
+	if fmt.Sprintf("%p", transport.Proxy) != fmt.Sprintf("%p", http.ProxyFromEnvironment) {
+		t.Error("client without proxy option does not use the proxy from the environment")
+	}
+}
+
+func TestBaseURL(t *testing.T) {
+	client, err := NewClient("http://localhost:8080/", Options{})
+	if err != nil {
+		t.Fatalf("NewClient: %s", err)
+	}
+	if got := client.BaseURL(); got != "http://localhost:8080" {
+		t.Errorf("BaseURL() = %q, want %q", got, "http://localhost:8080")
+	}
+	if got := client.Link("abc"); got != "http://localhost:8080/abc" {
+		t.Errorf("Link() = %q, want %q", got, "http://localhost:8080/abc")
+	}
+}